@@ -2,17 +2,21 @@ package env
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"os"
 	"path"
-
-	"github.com/s-hammon/p"
+	"sync"
 )
 
 type CacheFile struct {
-	ETags map[string]string `json:"eTags"`
+	ETags      map[string]string `json:"eTags"`
+	DeltaLinks map[string]string `json:"deltaLinks,omitempty"`
 }
 
+// cacheMu serializes read-modify-write cycles on the cache file.
+var cacheMu sync.Mutex
+
 func GetCachePath(dir string) string {
 	return path.Join(dir, "cache.json")
 }
@@ -25,18 +29,53 @@ func LoadCacheFile() CacheFile {
 	return cache
 }
 
+// WriteCacheFile replaces the value stored under key, leaving the rest of the
+// cache file untouched.
 func WriteCacheFile(key string, val map[string]string) {
-	var cache CacheFile
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	cache := LoadCacheFile()
 
 	switch key {
 	default:
 	case "eTags":
 		cache.ETags = val
+	case "deltaLinks":
+		cache.DeltaLinks = val
 	}
 
+	if err := writeCacheFile(cache); err != nil {
+		log.Println(err)
+	}
+}
+
+func writeCacheFile(cache CacheFile) error {
 	data, _ := json.MarshalIndent(cache, "", "  ")
 	if err := os.WriteFile(homeDirCacheFile, data, 0600); err != nil {
-		msg := p.Format("couldn't write to %s: %v", homeDirCacheFile, err)
-		log.Println(msg)
+		return fmt.Errorf("couldn't write to %s: %v", homeDirCacheFile, err)
+	}
+
+	return nil
+}
+
+// DeltaLinkCache stores delta links in the cache file, next to the ETags.
+type DeltaLinkCache struct{}
+
+func (DeltaLinkCache) Load(key string) (string, bool, error) {
+	link, ok := LoadCacheFile().DeltaLinks[key]
+	return link, ok, nil
+}
+
+func (DeltaLinkCache) Save(key, link string) error {
+	cacheMu.Lock()
+	defer cacheMu.Unlock()
+
+	cache := LoadCacheFile()
+	if cache.DeltaLinks == nil {
+		cache.DeltaLinks = make(map[string]string)
 	}
+	cache.DeltaLinks[key] = link
+
+	return writeCacheFile(cache)
 }
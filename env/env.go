@@ -14,6 +14,7 @@ var (
 	homeDir           = os.Getenv("GRAPH_HOME_DIR")
 	homeDirConfigFile = ""
 	homeDirCacheFile  = ""
+	homeDirTokenFile  = ""
 )
 
 func init() {
@@ -29,6 +30,8 @@ func init() {
 	if homeDir != "" && !pathExists(homeDirCacheFile) {
 		WriteCacheFile("", make(map[string]string))
 	}
+
+	homeDirTokenFile = GetTokenPath(homeDir)
 }
 
 func SetHomeDir(name string) string {
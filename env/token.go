@@ -0,0 +1,53 @@
+package env
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+
+	"golang.org/x/oauth2"
+)
+
+func GetTokenPath(dir string) string {
+	return path.Join(dir, "token.json")
+}
+
+// TokenFile stores the signed-in user's access and refresh tokens in
+// token.json, readable only by the current user.
+type TokenFile struct{}
+
+func (TokenFile) Load() (*oauth2.Token, bool, error) {
+	data, err := os.ReadFile(homeDirTokenFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, fmt.Errorf("couldn't read %s: %v", homeDirTokenFile, err)
+	}
+
+	var tok oauth2.Token
+	if err := json.Unmarshal(data, &tok); err != nil {
+		return nil, false, fmt.Errorf("json.Unmarshal: %v", err)
+	}
+
+	return &tok, true, nil
+}
+
+func (TokenFile) Save(tok *oauth2.Token) error {
+	data, _ := json.MarshalIndent(tok, "", "  ") //#nosec G117
+	if err := os.WriteFile(homeDirTokenFile, data, 0600); err != nil {
+		return fmt.Errorf("couldn't write to %s: %v", homeDirTokenFile, err)
+	}
+
+	return nil
+}
+
+func (TokenFile) Delete() error {
+	if err := os.Remove(homeDirTokenFile); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("couldn't remove %s: %v", homeDirTokenFile, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const attachmentsPath = "/groups/group1/threads/thread1/posts/post1/attachments"
+
+func TestAttachmentsGet(t *testing.T) {
+	server := newTestServer(t, http.MethodGet, attachmentsPath, `{"value":[
+		{"@odata.type":"#microsoft.graph.fileAttachment","id":"a1","name":"notes.txt","contentBytes":"aGVsbG8="},
+		{"@odata.type":"#microsoft.graph.itemAttachment","id":"a2","name":"Invite"},
+		{"@odata.type":"#microsoft.graph.referenceAttachment","id":"a3","name":"Spec"}
+	]}`)
+	defer server.Close()
+
+	client := newClient(server)
+
+	attachments, err := client.Groups().ById("group1").Threads().ById("thread1").Posts().ById("post1").Attachments().Get(context.Background())
+	require.NoError(t, err)
+	require.Len(t, attachments, 3)
+
+	file, ok := attachments[0].File()
+	require.True(t, ok)
+	require.Equal(t, []byte("hello"), file.ContentBytes)
+
+	_, ok = attachments[0].Item()
+	require.False(t, ok)
+
+	item, ok := attachments[1].Item()
+	require.True(t, ok)
+	require.Equal(t, "Invite", item.Name)
+
+	ref, ok := attachments[2].Reference()
+	require.True(t, ok)
+	require.Equal(t, "a3", ref.ID)
+}
+
+func TestAttachmentDownload(t *testing.T) {
+	server := newTestServer(t, http.MethodGet, attachmentsPath+"/a1/$value", "hello")
+	defer server.Close()
+
+	client := newClient(server)
+
+	var buf bytes.Buffer
+	n, err := client.Groups().ById("group1").Threads().ById("thread1").Posts().ById("post1").Attachments().ById("a1").Download(context.Background(), &buf)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), n)
+	require.Equal(t, "hello", buf.String())
+}
+
+// cancelWriter cancels a context once the first chunk has been written.
+type cancelWriter struct {
+	cancel context.CancelFunc
+}
+
+func (w cancelWriter) Write(p []byte) (int, error) {
+	w.cancel()
+	return len(p), nil
+}
+
+func TestAttachmentDownloadCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		for range 3 {
+			w.Write([]byte("chunk"))
+			w.(http.Flusher).Flush()
+			time.Sleep(50 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	client := newClient(server)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err := client.Groups().ById("group1").Threads().ById("thread1").Posts().ById("post1").Attachments().ById("a1").Download(ctx, cancelWriter{cancel})
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestReplyWithAttachment(t *testing.T) {
+	var body struct {
+		Post struct {
+			Attachments []map[string]any `json:"attachments"`
+		} `json:"post"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/groups/group1/threads/thread1/reply", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	client := newClient(server)
+
+	err := client.Groups().ById("group1").Threads().ById("thread1").Reply(context.Background(), TextBody("see attached"),
+		NewFileAttachment("report.json", []byte(`{"a":1}`)))
+	require.NoError(t, err)
+
+	require.Equal(t, []map[string]any{{
+		"@odata.type":  "#microsoft.graph.fileAttachment",
+		"name":         "report.json",
+		"contentType":  "application/json",
+		"contentBytes": "eyJhIjoxfQ==",
+	}}, body.Post.Attachments)
+}
@@ -0,0 +1,529 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	batchResource string = "$batch"
+
+	// MaxBatchSize is the maximum number of requests Graph accepts in a
+	// single JSON batch.
+	MaxBatchSize = 20
+)
+
+// BatchRequest is a single request within a JSON batch. These are created by
+// the *Request methods of the request builders, e.g.
+//
+//	req := client.Planner().Tasks().ById(taskId).PatchRequest(params)
+type BatchRequest struct {
+	ID        string            `json:"id"`
+	Method    string            `json:"method"`
+	URL       string            `json:"url"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	Body      any               `json:"body,omitempty"`
+	DependsOn []string          `json:"dependsOn,omitempty"`
+
+	// path is the absolute URL of the resource, which is also its key in
+	// eTagCache.
+	path      string
+	needsETag bool
+	deps      []*BatchRequest
+}
+
+func newBatchRequest(c *Client, method, path string, body any) *BatchRequest {
+	req := &BatchRequest{
+		Method:  method,
+		URL:     strings.TrimPrefix(path, c.BaseURL),
+		Headers: make(map[string]string),
+		Body:    body,
+		path:    path,
+	}
+	if body != nil {
+		req.Headers["Content-Type"] = "application/json"
+	}
+
+	return req
+}
+
+func newBatchPatchRequest(c *Client, path string, body any) *BatchRequest {
+	req := newBatchRequest(c, http.MethodPatch, path, body)
+	req.Headers["Prefer"] = "return=representation"
+	req.needsETag = true
+
+	return req
+}
+
+// withQuery applies the OData query options in q to the request.
+func (r *BatchRequest) withQuery(q *Query) *BatchRequest {
+	r.URL = q.url(r.URL)
+	for k := range q.header() {
+		r.Headers[k] = q.header().Get(k)
+	}
+
+	return r
+}
+
+// After makes Graph run r only once all of reqs have succeeded. Requests
+// which depend on each other are always sent in the same batch.
+func (r *BatchRequest) After(reqs ...*BatchRequest) *BatchRequest {
+	r.deps = append(r.deps, reqs...)
+	return r
+}
+
+// BatchResponse is the result of a single request within a JSON batch. Err
+// is set to an *Error if the request was unsuccessful.
+type BatchResponse struct {
+	ID         string
+	StatusCode int
+	Header     http.Header
+	Body       json.RawMessage
+	Err        error
+}
+
+// Decode unmarshals the response body into v, or returns the request's error.
+func (r BatchResponse) Decode(v any) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	if len(r.Body) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(r.Body, v); err != nil {
+		return fmt.Errorf("json.Unmarshal: %v", err)
+	}
+
+	return nil
+}
+
+// BatchResult decodes a batch response into T.
+func BatchResult[T any](resp BatchResponse) (T, error) {
+	var ret T
+	err := resp.Decode(&ret)
+	return ret, err
+}
+
+type BatchRequestBuilder struct {
+	c    *Client
+	path string
+	reqs []*BatchRequest
+}
+
+func (c *Client) Batch() *BatchRequestBuilder {
+	return &BatchRequestBuilder{
+		c:    c,
+		path: joinPath(c.BaseURL, batchResource),
+	}
+}
+
+// Add queues requests to the batch. Requests without an ID are numbered in
+// the order they are added, skipping the IDs of the others. The requests
+// aren't changed, so they can be added to another batch later.
+func (b *BatchRequestBuilder) Add(reqs ...*BatchRequest) *BatchRequestBuilder {
+	b.reqs = append(b.reqs, reqs...)
+	return b
+}
+
+// Send packs the queued requests into as many $batch round-trips as needed
+// and returns one BatchResponse per request, in the order they were added.
+// Requests that Graph throttles within a batch are retried according to the
+// client's retry policy and their own Retry-After.
+//
+// The returned error is only set if a batch as a whole couldn't be sent.
+func (b *BatchRequestBuilder) Send(ctx context.Context) ([]BatchResponse, error) {
+	reqs, err := b.prepare()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]BatchResponse, len(reqs))
+	pending := b.fillETags(ctx, reqs, results)
+
+	for attempt := 1; len(pending) > 0; attempt++ {
+		chunks, err := chunkBatch(pending)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, chunk := range chunks {
+			resps, err := b.c.sendBatch(ctx, b.path, withinChunk(chunk))
+			if err != nil {
+				return nil, err
+			}
+
+			for _, resp := range resps {
+				results[resp.ID] = resp
+			}
+		}
+
+		retry := b.retryable(pending, results, attempt)
+		if len(retry) == 0 {
+			break
+		}
+
+		var delay time.Duration
+		for _, req := range retry {
+			delay = max(delay, b.c.retry.backoff(attempt, results[req.ID].Header))
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+
+		pending = retry
+	}
+
+	ret := make([]BatchResponse, 0, len(reqs))
+	for _, req := range reqs {
+		resp, ok := results[req.ID]
+		switch {
+		case !ok:
+			resp = BatchResponse{ID: req.ID, Err: fmt.Errorf("no response for request %s in batch", req.ID)}
+		case resp.StatusCode == http.StatusPreconditionFailed:
+			b.c.deleteETag(req.path)
+		case resp.Err == nil:
+			if key := batchETagKey(req, resp); key != "" {
+				b.c.putETagFromBody(key, resp.Body)
+			}
+		}
+		ret = append(ret, resp)
+	}
+
+	return ret, nil
+}
+
+// prepare returns copies of the queued requests to send, numbering those
+// without an ID and listing the IDs of the requests passed to After in
+// dependsOn.
+func (b *BatchRequestBuilder) prepare() ([]*BatchRequest, error) {
+	used := make(map[string]bool, len(b.reqs))
+	for _, req := range b.reqs {
+		if req.ID == "" {
+			continue
+		}
+		if used[req.ID] {
+			return nil, fmt.Errorf("duplicate request ID %q in batch", req.ID)
+		}
+		used[req.ID] = true
+	}
+
+	var (
+		next   int
+		ret    = make([]*BatchRequest, len(b.reqs))
+		copies = make(map[*BatchRequest]*BatchRequest, len(b.reqs))
+	)
+	for i, req := range b.reqs {
+		cp := *req
+		cp.Headers = maps.Clone(req.Headers)
+		if cp.Headers == nil {
+			cp.Headers = make(map[string]string)
+		}
+		cp.DependsOn = slices.Clone(req.DependsOn)
+		if cp.ID == "" {
+			next++
+			for used[strconv.Itoa(next)] {
+				next++
+			}
+			cp.ID = strconv.Itoa(next)
+		}
+
+		ret[i] = &cp
+		copies[req] = &cp
+	}
+
+	for _, req := range ret {
+		for _, dep := range req.deps {
+			cp, ok := copies[dep]
+			if !ok {
+				return nil, fmt.Errorf("request %s depends on a request that wasn't added to the batch", req.ID)
+			}
+			if !slices.Contains(req.DependsOn, cp.ID) {
+				req.DependsOn = append(req.DependsOn, cp.ID)
+			}
+		}
+	}
+
+	return ret, nil
+}
+
+// fillETags sets If-Match on every request that needs it, fetching any ETags
+// missing from the cache in batches of their own. It returns the requests
+// that are ready to send; those whose ETag couldn't be fetched are marked as
+// failed in results instead.
+func (b *BatchRequestBuilder) fillETags(ctx context.Context, reqs []*BatchRequest, results map[string]BatchResponse) []*BatchRequest {
+	var missing []*BatchRequest
+	seen := make(map[string]bool)
+	for _, req := range reqs {
+		if !req.needsETag || req.Headers["If-Match"] != "" || seen[req.path] {
+			continue
+		}
+		if _, ok := b.c.getETag(req.path); !ok {
+			seen[req.path] = true
+			missing = append(missing, newBatchRequest(b.c, http.MethodGet, req.path, nil))
+		}
+	}
+
+	fetchErrs := make(map[string]error)
+	if len(missing) > 0 {
+		resps, err := b.c.Batch().Add(missing...).Send(ctx)
+		for i, req := range missing {
+			switch {
+			case err != nil:
+				fetchErrs[req.path] = err
+			case resps[i].Err != nil:
+				fetchErrs[req.path] = resps[i].Err
+			}
+		}
+	}
+
+	failed := make(map[string]bool)
+	for _, req := range reqs {
+		if req.needsETag && req.Headers["If-Match"] == "" {
+			eTag, ok := b.c.getETag(req.path)
+			if !ok {
+				var err any = "eTag not in response body"
+				if fetchErr, ok := fetchErrs[req.path]; ok {
+					err = fetchErr
+				}
+
+				results[req.ID] = BatchResponse{ID: req.ID, Err: newRefreshETagErr(err)}
+				failed[req.ID] = true
+				continue
+			}
+			req.Headers["If-Match"] = eTag
+		}
+	}
+
+	// requests depending on one that can't be sent would fail anyway, like
+	// Graph fails them with 424 Failed Dependency
+	for changed := true; changed; {
+		changed = false
+		for _, req := range reqs {
+			if failed[req.ID] {
+				continue
+			}
+			i := slices.IndexFunc(req.DependsOn, func(id string) bool { return failed[id] })
+			if i < 0 {
+				continue
+			}
+
+			results[req.ID] = BatchResponse{
+				ID:         req.ID,
+				StatusCode: http.StatusFailedDependency,
+				Err:        fmt.Errorf("request %s depends on request %s, which failed", req.ID, req.DependsOn[i]),
+			}
+			failed[req.ID] = true
+			changed = true
+		}
+	}
+
+	return slices.DeleteFunc(slices.Clone(reqs), func(req *BatchRequest) bool {
+		return failed[req.ID]
+	})
+}
+
+// withinChunk returns copies of the requests in chunk whose dependsOn only
+// lists requests in the chunk: Graph rejects a whole batch that refers to a
+// request it doesn't contain, e.g. a dependency which already succeeded in
+// an earlier attempt.
+func withinChunk(chunk []*BatchRequest) []*BatchRequest {
+	ids := make(map[string]bool, len(chunk))
+	for _, req := range chunk {
+		ids[req.ID] = true
+	}
+
+	ret := make([]*BatchRequest, len(chunk))
+	for i, req := range chunk {
+		cp := *req
+		cp.DependsOn = slices.DeleteFunc(slices.Clone(req.DependsOn), func(id string) bool {
+			return !ids[id]
+		})
+		ret[i] = &cp
+	}
+
+	return ret
+}
+
+// batchETagKey returns the eTagCache key of the resource a batched request
+// returned, or "" if there is none. Like handlePatchPostResp, a POST
+// creating an entity caches it under the new entity's path rather than the
+// collection's; other POSTs aren't cached.
+func batchETagKey(req *BatchRequest, resp BatchResponse) string {
+	if req.Method != http.MethodPost {
+		return req.path
+	}
+
+	var created struct {
+		ID string `json:"id"`
+	}
+	if resp.StatusCode != http.StatusCreated || json.Unmarshal(resp.Body, &created) != nil || created.ID == "" {
+		return ""
+	}
+
+	return joinPath(req.path, created.ID)
+}
+
+// retryable returns the requests to send again: those Graph responded to
+// with a retryable status, plus any requests that failed because one of
+// those was a dependency.
+func (b *BatchRequestBuilder) retryable(pending []*BatchRequest, results map[string]BatchResponse, attempt int) []*BatchRequest {
+	if attempt >= b.c.retry.MaxAttempts {
+		return nil
+	}
+
+	retry := make(map[string]bool)
+	for _, req := range pending {
+		if b.c.retry.retryable(results[req.ID].StatusCode) {
+			retry[req.ID] = true
+		}
+	}
+	if len(retry) == 0 {
+		return nil
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for _, req := range pending {
+			if retry[req.ID] || results[req.ID].StatusCode != http.StatusFailedDependency {
+				continue
+			}
+			if slices.ContainsFunc(req.DependsOn, func(id string) bool { return retry[id] }) {
+				retry[req.ID] = true
+				changed = true
+			}
+		}
+	}
+
+	return slices.DeleteFunc(slices.Clone(pending), func(req *BatchRequest) bool {
+		return !retry[req.ID]
+	})
+}
+
+// chunkBatch splits reqs into batches of at most MaxBatchSize, keeping
+// requests which depend on each other together.
+func chunkBatch(reqs []*BatchRequest) ([][]*BatchRequest, error) {
+	byID := make(map[string]*BatchRequest, len(reqs))
+	for _, req := range reqs {
+		byID[req.ID] = req
+	}
+
+	// union-find over dependencies
+	parent := make(map[string]string, len(reqs))
+	var find func(string) string
+	find = func(id string) string {
+		if parent[id] == "" || parent[id] == id {
+			return id
+		}
+		parent[id] = find(parent[id])
+		return parent[id]
+	}
+	for _, req := range reqs {
+		for _, dep := range req.DependsOn {
+			if _, ok := byID[dep]; ok {
+				parent[find(req.ID)] = find(dep)
+			}
+		}
+	}
+
+	var (
+		order  []string
+		groups = make(map[string][]*BatchRequest)
+	)
+	for _, req := range reqs {
+		root := find(req.ID)
+		if _, ok := groups[root]; !ok {
+			order = append(order, root)
+		}
+		groups[root] = append(groups[root], req)
+	}
+
+	var (
+		ret   [][]*BatchRequest
+		chunk []*BatchRequest
+	)
+	for _, root := range order {
+		group := groups[root]
+		if len(group) > MaxBatchSize {
+			return nil, fmt.Errorf("%d requests depend on each other, but a batch holds at most %d", len(group), MaxBatchSize)
+		}
+
+		if len(chunk)+len(group) > MaxBatchSize {
+			ret = append(ret, chunk)
+			chunk = nil
+		}
+		chunk = append(chunk, group...)
+	}
+	if len(chunk) > 0 {
+		ret = append(ret, chunk)
+	}
+
+	return ret, nil
+}
+
+type batchPayload struct {
+	Requests []*BatchRequest `json:"requests"`
+}
+
+type batchResponseItem struct {
+	ID      string            `json:"id"`
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers"`
+	Body    json.RawMessage   `json:"body"`
+}
+
+type batchResponsePayload struct {
+	Responses []batchResponseItem `json:"responses"`
+}
+
+func (c *Client) sendBatch(ctx context.Context, path string, reqs []*BatchRequest) ([]BatchResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, path, toBody(batchPayload{Requests: reqs}))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("client.Do: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, requestErr(resp)
+	}
+	defer resp.Body.Close()
+
+	var payload batchResponsePayload
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("error decoding batch response body: %v", err)
+	}
+
+	ret := make([]BatchResponse, 0, len(payload.Responses))
+	for _, item := range payload.Responses {
+		header := make(http.Header, len(item.Headers))
+		for k, v := range item.Headers {
+			header.Set(k, v)
+		}
+
+		resp := BatchResponse{
+			ID:         item.ID,
+			StatusCode: item.Status,
+			Header:     header,
+			Body:       item.Body,
+		}
+		if item.Status >= 400 {
+			resp.Err = newError(item.Status, header, item.Body)
+		}
+
+		ret = append(ret, resp)
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,300 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testBatchRequest struct {
+	ID        string            `json:"id"`
+	Method    string            `json:"method"`
+	URL       string            `json:"url"`
+	Headers   map[string]string `json:"headers"`
+	Body      json.RawMessage   `json:"body"`
+	DependsOn []string          `json:"dependsOn"`
+}
+
+func newBatchServer(t *testing.T, handle func(req testBatchRequest) batchResponseItem) (*httptest.Server, *[]int) {
+	var sizes []int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/$batch", r.URL.Path)
+
+		var payload struct {
+			Requests []testBatchRequest `json:"requests"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		require.LessOrEqual(t, len(payload.Requests), MaxBatchSize)
+		sizes = append(sizes, len(payload.Requests))
+
+		var resp batchResponsePayload
+		for _, req := range payload.Requests {
+			item := handle(req)
+			item.ID = req.ID
+			resp.Responses = append(resp.Responses, item)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(resp)
+	}))
+
+	return server, &sizes
+}
+
+func TestBatchSplitsRequests(t *testing.T) {
+	server, sizes := newBatchServer(t, func(req testBatchRequest) batchResponseItem {
+		require.Equal(t, http.MethodPatch, req.Method)
+		require.Equal(t, `W/"etag"`, req.Headers["If-Match"])
+
+		var params PatchTaskParams
+		require.NoError(t, json.Unmarshal(req.Body, &params))
+		title, _ := params.Title.Get()
+
+		return batchResponseItem{
+			Status: http.StatusOK,
+			Body:   json.RawMessage(fmt.Sprintf(`{"id":%q,"title":%q}`, req.URL[len("/planner/tasks/"):], title)),
+		}
+	})
+	defer server.Close()
+
+	client := newClient(server)
+	client.eTagCache = make(map[string]string)
+
+	batch := client.Batch()
+	for i := range 25 {
+		task := client.Planner().Tasks().ById("task" + strconv.Itoa(i))
+		client.eTagCache[task.path] = `W/"etag"`
+		batch.Add(task.PatchRequest(PatchTaskParams{Title: Set("Task " + strconv.Itoa(i))}))
+	}
+
+	resps, err := batch.Send(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []int{20, 5}, *sizes)
+	require.Len(t, resps, 25)
+
+	task, err := BatchResult[Task](resps[24])
+	require.NoError(t, err)
+	require.Equal(t, "task24", task.ID)
+	require.Equal(t, "Task 24", task.Title)
+}
+
+func TestBatchRetriesThrottledRequests(t *testing.T) {
+	attempts := make(map[string]int)
+	server, sizes := newBatchServer(t, func(req testBatchRequest) batchResponseItem {
+		attempts[req.Method+" "+req.URL]++
+
+		switch {
+		case req.Method == http.MethodGet:
+			// ETag fetch for the PATCH below
+			return batchResponseItem{Status: http.StatusOK, Body: json.RawMessage(`{"@odata.etag":"W/\"fresh\""}`)}
+		case req.Method == http.MethodPost && attempts["POST /planner/buckets"] == 1:
+			return batchResponseItem{Status: http.StatusTooManyRequests, Headers: map[string]string{"Retry-After": "0"}}
+		case req.Method == http.MethodPatch && attempts["POST /planner/buckets"] == 1:
+			require.Equal(t, []string{"1"}, req.DependsOn)
+			return batchResponseItem{Status: http.StatusFailedDependency}
+		case req.Method == http.MethodPatch:
+			require.Equal(t, `W/"fresh"`, req.Headers["If-Match"])
+			return batchResponseItem{Status: http.StatusOK, Body: json.RawMessage(`{"id":"task1","bucketId":"bucket1"}`)}
+		default:
+			return batchResponseItem{Status: http.StatusCreated, Body: json.RawMessage(`{"id":"bucket1"}`)}
+		}
+	})
+	defer server.Close()
+
+	client := newClient(server).WithRetryPolicy(RetryPolicy{
+		MaxAttempts:          3,
+		BaseDelay:            time.Millisecond,
+		RetryableStatusCodes: []int{http.StatusTooManyRequests},
+	})
+	client.eTagCache = make(map[string]string)
+
+	bucket := client.Planner().Buckets().PostRequest(PostBucketParams{Name: "Bucket 1", PlanID: "plan1"})
+	task := client.Planner().Tasks().ById("task1").PatchRequest(PatchTaskParams{BucketID: Set("bucket1")}).After(bucket)
+
+	resps, err := client.Batch().Add(bucket, task).Send(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2, 2}, *sizes)
+	require.Equal(t, 2, attempts["PATCH /planner/tasks/task1"])
+
+	for _, resp := range resps {
+		require.NoError(t, resp.Err)
+	}
+}
+
+func TestChunkBatchKeepsDependenciesTogether(t *testing.T) {
+	var reqs []*BatchRequest
+	for i := range 30 {
+		req := &BatchRequest{ID: strconv.Itoa(i + 1)}
+		if i == 25 {
+			req.DependsOn = []string{"1"}
+		}
+		reqs = append(reqs, req)
+	}
+
+	chunks, err := chunkBatch(reqs)
+	require.NoError(t, err)
+	require.Len(t, chunks, 2)
+	require.Len(t, chunks[0], 20)
+	require.Equal(t, "26", chunks[0][1].ID)
+}
+
+func TestBatchRetriesOnlyDependent(t *testing.T) {
+	attempts := make(map[string]int)
+	server, sizes := newBatchServer(t, func(req testBatchRequest) batchResponseItem {
+		attempts[req.Method+" "+req.URL]++
+
+		if req.Method == http.MethodPost {
+			return batchResponseItem{Status: http.StatusCreated, Body: json.RawMessage(`{"id":"bucket1","@odata.etag":"W/\"bucket\""}`)}
+		}
+		if attempts["PATCH /planner/tasks/task1"] == 1 {
+			require.Equal(t, []string{"1"}, req.DependsOn)
+			return batchResponseItem{Status: http.StatusTooManyRequests, Headers: map[string]string{"Retry-After": "0"}}
+		}
+
+		// the bucket isn't resent, so the retry mustn't depend on it
+		require.Empty(t, req.DependsOn)
+		return batchResponseItem{Status: http.StatusOK, Body: json.RawMessage(`{"id":"task1","@odata.etag":"W/\"task\""}`)}
+	})
+	defer server.Close()
+
+	client := newClient(server).WithRetryPolicy(RetryPolicy{
+		MaxAttempts:          3,
+		BaseDelay:            time.Millisecond,
+		RetryableStatusCodes: []int{http.StatusTooManyRequests},
+	})
+	client.eTagCache = make(map[string]string)
+
+	tasks := client.Planner().Tasks()
+	buckets := client.Planner().Buckets()
+	client.eTagCache[tasks.ById("task1").path] = `W/"etag"`
+
+	bucket := buckets.PostRequest(PostBucketParams{Name: "Bucket 1", PlanID: "plan1"})
+	task := tasks.ById("task1").PatchRequest(PatchTaskParams{BucketID: Set("bucket1")}).After(bucket)
+
+	resps, err := client.Batch().Add(bucket, task).Send(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []int{2, 1}, *sizes)
+	require.Equal(t, 1, attempts["POST /planner/buckets"])
+
+	for _, resp := range resps {
+		require.NoError(t, resp.Err)
+	}
+
+	// a created entity's ETag is cached under its own path
+	_, ok := client.getETag(buckets.path)
+	require.False(t, ok)
+	eTag, _ := client.getETag(joinPath(buckets.path, "bucket1"))
+	require.Equal(t, `W/"bucket"`, eTag)
+}
+
+func TestBatchFailsDependentsOfETagFetch(t *testing.T) {
+	var sent []string
+	server, _ := newBatchServer(t, func(req testBatchRequest) batchResponseItem {
+		sent = append(sent, req.Method+" "+req.URL)
+
+		if req.Method == http.MethodGet {
+			return batchResponseItem{Status: http.StatusNotFound}
+		}
+		return batchResponseItem{Status: http.StatusOK, Body: json.RawMessage(`{}`)}
+	})
+	defer server.Close()
+
+	client := newClient(server)
+	client.eTagCache = make(map[string]string)
+
+	task := client.Planner().Tasks().ById("task1").PatchRequest(PatchTaskParams{Title: Set("Renamed")})
+	details := client.Planner().Tasks().ById("task2").GetRequest().After(task)
+
+	resps, err := client.Batch().Add(task, details).Send(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"GET /planner/tasks/task1"}, sent)
+
+	require.Error(t, resps[0].Err)
+	require.Equal(t, http.StatusFailedDependency, resps[1].StatusCode)
+	require.ErrorContains(t, resps[1].Err, "depends on request 1")
+}
+
+func TestBatchNumbersAroundExplicitIDs(t *testing.T) {
+	var ids []string
+	server, _ := newBatchServer(t, func(req testBatchRequest) batchResponseItem {
+		ids = append(ids, req.ID)
+		return batchResponseItem{Status: http.StatusOK, Body: json.RawMessage(`{}`)}
+	})
+	defer server.Close()
+
+	client := newClient(server)
+
+	named := client.Planner().Tasks().ById("task1").GetRequest()
+	named.ID = "2"
+	first := client.Planner().Tasks().ById("task2").GetRequest()
+	second := client.Planner().Tasks().ById("task3").GetRequest()
+
+	resps, err := client.Batch().Add(named, first, second).Send(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"2", "1", "3"}, ids)
+	require.Equal(t, "3", resps[2].ID)
+
+	// the requests aren't numbered in place
+	require.Empty(t, first.ID)
+	require.Empty(t, second.ID)
+
+	other := client.Planner().Tasks().ById("task4").GetRequest()
+	other.ID = "2"
+	_, err = client.Batch().Add(named, other).Send(context.Background())
+	require.ErrorContains(t, err, `duplicate request ID "2"`)
+}
+
+func TestBatchDoesNotChangeRequests(t *testing.T) {
+	eTags := []string{`W/"stale"`, `W/"fresh"`}
+	var ifMatch []string
+	server, _ := newBatchServer(t, func(req testBatchRequest) batchResponseItem {
+		if req.Method == http.MethodGet {
+			return batchResponseItem{Status: http.StatusOK, Body: json.RawMessage(`{"@odata.etag":` + strconv.Quote(eTags[0]) + `}`)}
+		}
+
+		if req.Method == http.MethodPost {
+			return batchResponseItem{Status: http.StatusCreated, Body: json.RawMessage(`{"id":"bucket1"}`)}
+		}
+
+		ifMatch = append(ifMatch, req.Headers["If-Match"])
+		if req.Headers["If-Match"] == `W/"stale"` {
+			eTags = eTags[1:]
+			return batchResponseItem{Status: http.StatusPreconditionFailed}
+		}
+		return batchResponseItem{Status: http.StatusOK, Body: json.RawMessage(`{"id":"task1"}`)}
+	})
+	defer server.Close()
+
+	client := newClient(server)
+	client.eTagCache = make(map[string]string)
+
+	task := client.Planner().Tasks().ById("task1")
+	client.eTagCache[task.path] = `W/"stale"`
+
+	bucket := client.Planner().Buckets().PostRequest(PostBucketParams{Name: "Bucket 1", PlanID: "plan1"})
+	patch := task.PatchRequest(PatchTaskParams{Title: Set("Renamed")}).After(bucket)
+	resps, err := client.Batch().Add(bucket, patch).Send(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, http.StatusPreconditionFailed, resps[1].StatusCode)
+
+	// the 412 evicted the stale ETag, and the request itself is unchanged
+	_, ok := client.getETag(task.path)
+	require.False(t, ok)
+	require.Empty(t, patch.ID)
+	require.Empty(t, patch.DependsOn)
+	require.NotContains(t, patch.Headers, "If-Match")
+
+	resps, err = client.Batch().Add(bucket, patch).Send(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, resps[1].Err)
+	require.Equal(t, []string{`W/"stale"`, `W/"fresh"`}, ifMatch)
+}
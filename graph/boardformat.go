@@ -0,0 +1,133 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	assignedToTaskBoardFormatResource string = "assignedToTaskBoardFormat"
+	bucketTaskBoardFormatResource     string = "bucketTaskBoardFormat"
+	progressTaskBoardFormatResource   string = "progressTaskBoardFormat"
+)
+
+// AssignedToTaskBoardFormat orders a task in the "Assigned to" view of the
+// board, which has a column per assignee.
+type AssignedToTaskBoardFormat struct {
+	OdataEtag            string            `json:"@odata.etag"`
+	ID                   string            `json:"id"`
+	OrderHintsByAssignee map[string]string `json:"orderHintsByAssignee"`
+	UnassignedOrderHint  string            `json:"unassignedOrderHint"`
+}
+
+// OrderHint returns the order hint of the task in the column of the given
+// user, or in the "Unassigned" column if userID is empty.
+func (f AssignedToTaskBoardFormat) OrderHint(userID string) string {
+	if userID == "" {
+		return f.UnassignedOrderHint
+	}
+
+	return f.OrderHintsByAssignee[userID]
+}
+
+type PatchAssignedToTaskBoardFormatParams struct {
+	OrderHintsByAssignee map[string]string `json:"orderHintsByAssignee,omitempty"`
+	UnassignedOrderHint  string            `json:"unassignedOrderHint,omitempty"`
+}
+
+// BucketTaskBoardFormat orders a task in the "Bucket" view of the board.
+type BucketTaskBoardFormat struct {
+	OdataEtag string `json:"@odata.etag"`
+	ID        string `json:"id"`
+	OrderHint string `json:"orderHint"`
+}
+
+type PatchBucketTaskBoardFormatParams struct {
+	OrderHint string `json:"orderHint"`
+}
+
+// ProgressTaskBoardFormat orders a task in the "Progress" view of the board.
+type ProgressTaskBoardFormat struct {
+	OdataEtag string `json:"@odata.etag"`
+	ID        string `json:"id"`
+	OrderHint string `json:"orderHint"`
+}
+
+type PatchProgressTaskBoardFormatParams struct {
+	OrderHint string `json:"orderHint"`
+}
+
+// TaskBoardFormatRequestBuilder reads and updates one of the task board
+// formats of a task, where T is the format and P its patch parameters.
+type TaskBoardFormatRequestBuilder[T, P any] struct {
+	Id       string
+	c        *Client
+	path     string
+	query    *Query
+	conflict conflictHandler[T, P]
+}
+
+func newTaskBoardFormatRequestBuilder[T, P any](r *TaskRequestBuilder, resource string) *TaskBoardFormatRequestBuilder[T, P] {
+	return &TaskBoardFormatRequestBuilder[T, P]{
+		Id:   r.Id,
+		c:    r.c,
+		path: joinPath(r.path, resource),
+	}
+}
+
+func (r *TaskRequestBuilder) AssignedToTaskBoardFormat() *TaskBoardFormatRequestBuilder[AssignedToTaskBoardFormat, PatchAssignedToTaskBoardFormatParams] {
+	return newTaskBoardFormatRequestBuilder[AssignedToTaskBoardFormat, PatchAssignedToTaskBoardFormatParams](r, assignedToTaskBoardFormatResource)
+}
+
+func (r *TaskRequestBuilder) BucketTaskBoardFormat() *TaskBoardFormatRequestBuilder[BucketTaskBoardFormat, PatchBucketTaskBoardFormatParams] {
+	return newTaskBoardFormatRequestBuilder[BucketTaskBoardFormat, PatchBucketTaskBoardFormatParams](r, bucketTaskBoardFormatResource)
+}
+
+func (r *TaskRequestBuilder) ProgressTaskBoardFormat() *TaskBoardFormatRequestBuilder[ProgressTaskBoardFormat, PatchProgressTaskBoardFormatParams] {
+	return newTaskBoardFormatRequestBuilder[ProgressTaskBoardFormat, PatchProgressTaskBoardFormatParams](r, progressTaskBoardFormatResource)
+}
+
+// Query sets the OData query options for the request.
+func (r *TaskBoardFormatRequestBuilder[T, P]) Query(q *Query) *TaskBoardFormatRequestBuilder[T, P] {
+	r.query = q
+	return r
+}
+
+func (r *TaskBoardFormatRequestBuilder[T, P]) Get(ctx context.Context) (T, error) {
+	var ret T
+
+	if err := get(ctx, r.c, r.query.url(r.path), r.query.header(), &ret); err != nil {
+		return ret, err
+	}
+
+	return ret, nil
+}
+
+// GetRequest returns the Get request for use in a batch.
+func (r *TaskBoardFormatRequestBuilder[T, P]) GetRequest() *BatchRequest {
+	return newBatchRequest(r.c, http.MethodGet, r.path, nil).withQuery(r.query)
+}
+
+func (r *TaskBoardFormatRequestBuilder[T, P]) Patch(ctx context.Context, params P) (T, error) {
+	return patchEntity(ctx, r.c, r.path, params, r.conflict)
+}
+
+// OnConflict sets what Patch does if the format changed since its ETag was
+// cached. The default is ConflictFail.
+func (r *TaskBoardFormatRequestBuilder[T, P]) OnConflict(policy ConflictPolicy) *TaskBoardFormatRequestBuilder[T, P] {
+	r.conflict.policy = policy
+	return r
+}
+
+// MergeOnConflict resolves conflicts with fn, see ConflictMerge.
+func (r *TaskBoardFormatRequestBuilder[T, P]) MergeOnConflict(fn MergeFunc[T, P]) *TaskBoardFormatRequestBuilder[T, P] {
+	r.conflict.policy = ConflictMerge
+	r.conflict.merge = fn
+	return r
+}
+
+// PatchRequest returns the Patch request for use in a batch. The If-Match
+// header is filled from the ETag cache when the batch is sent.
+func (r *TaskBoardFormatRequestBuilder[T, P]) PatchRequest(params P) *BatchRequest {
+	return newBatchPatchRequest(r.c, r.path, params)
+}
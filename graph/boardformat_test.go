@@ -0,0 +1,54 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBucketTaskBoardFormatGet(t *testing.T) {
+	server := newTestServer(t, http.MethodGet, "/planner/tasks/task1/bucketTaskBoardFormat", `{"@odata.etag":"W/\"1\"","id":"task1","orderHint":"8585"}`)
+	defer server.Close()
+
+	client := newClient(server)
+
+	format, err := client.Planner().Tasks().ById("task1").BucketTaskBoardFormat().Get(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "8585", format.OrderHint)
+
+	eTag, ok := client.getETag(server.URL + "/planner/tasks/task1/bucketTaskBoardFormat")
+	require.True(t, ok)
+	require.Equal(t, `W/"1"`, eTag)
+}
+
+func TestAssignedToTaskBoardFormatPatch(t *testing.T) {
+	var body PatchAssignedToTaskBoardFormatParams
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPatch, r.Method)
+		require.Equal(t, "/planner/tasks/task1/assignedToTaskBoardFormat", r.URL.Path)
+		require.Equal(t, `W/"test-etag"`, r.Header.Get("If-Match"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id":"task1","orderHintsByAssignee":{"user1":"8585"},"unassignedOrderHint":"8584"}`))
+	}))
+	defer server.Close()
+
+	client := newClient(server)
+	client.eTagCache = map[string]string{
+		server.URL + "/planner/tasks/task1/assignedToTaskBoardFormat": `W/"test-etag"`,
+	}
+
+	format, err := client.Planner().Tasks().ById("task1").AssignedToTaskBoardFormat().Patch(context.Background(), PatchAssignedToTaskBoardFormatParams{
+		OrderHintsByAssignee: map[string]string{"user1": OrderHintFirst},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"user1": OrderHintFirst}, body.OrderHintsByAssignee)
+	require.Equal(t, "8585", format.OrderHint("user1"))
+	require.Equal(t, "8584", format.OrderHint(""))
+}
@@ -0,0 +1,228 @@
+package graph
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1" //#nosec G505 -- x5t is defined as a SHA-1 thumbprint
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+const (
+	clientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+	// assertionLifetime is how long a client assertion is valid for. A new
+	// one is signed for every token request.
+	assertionLifetime = 10 * time.Minute
+)
+
+// Certificate is an X.509 certificate and its RSA private key, used to sign
+// the client assertions sent to the token endpoint instead of a client
+// secret. The certificate must be uploaded to the app registration.
+type Certificate struct {
+	Leaf *x509.Certificate
+	key  *rsa.PrivateKey
+}
+
+// LoadCertificate reads a certificate and its private key from a PEM file, or
+// from a PKCS#12 (.pfx/.p12) file protected by password.
+func LoadCertificate(path, password string) (*Certificate, error) {
+	data, err := os.ReadFile(path) //#nosec G304
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read certificate: %w", err)
+	}
+
+	return ParseCertificate(data, password)
+}
+
+// ParseCertificate parses a certificate and its private key, either PEM
+// encoded (password is ignored) or PKCS#12 encoded.
+func ParseCertificate(data []byte, password string) (*Certificate, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		return parsePEMCertificate(data)
+	}
+
+	key, leaf, _, err := pkcs12.DecodeChain(data, password)
+	if err != nil {
+		return nil, fmt.Errorf("pkcs12.DecodeChain: %v", err)
+	}
+
+	return newCertificate(leaf, key)
+}
+
+func parsePEMCertificate(data []byte) (*Certificate, error) {
+	var (
+		leaf *x509.Certificate
+		key  any
+		err  error
+	)
+
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		switch block.Type {
+		case "CERTIFICATE":
+			// the first certificate is the leaf, the others are its chain
+			if leaf == nil {
+				if leaf, err = x509.ParseCertificate(block.Bytes); err != nil {
+					return nil, fmt.Errorf("x509.ParseCertificate: %v", err)
+				}
+			}
+		case "PRIVATE KEY":
+			if key, err = x509.ParsePKCS8PrivateKey(block.Bytes); err != nil {
+				return nil, fmt.Errorf("x509.ParsePKCS8PrivateKey: %v", err)
+			}
+		case "RSA PRIVATE KEY":
+			if key, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+				return nil, fmt.Errorf("x509.ParsePKCS1PrivateKey: %v", err)
+			}
+		case "ENCRYPTED PRIVATE KEY":
+			return nil, errors.New("encrypted PEM private keys aren't supported; use a PKCS#12 file instead")
+		}
+	}
+
+	if leaf == nil {
+		return nil, errors.New("no certificate found in PEM data")
+	}
+	if key == nil {
+		return nil, errors.New("no private key found in PEM data")
+	}
+
+	return newCertificate(leaf, key)
+}
+
+func newCertificate(leaf *x509.Certificate, key any) (*Certificate, error) {
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T; Azure AD requires an RSA key", key)
+	}
+
+	pub, ok := leaf.PublicKey.(*rsa.PublicKey)
+	if !ok || !pub.Equal(rsaKey.Public()) {
+		return nil, errors.New("private key doesn't match the certificate")
+	}
+
+	return &Certificate{Leaf: leaf, key: rsaKey}, nil
+}
+
+// Thumbprint returns the base64url-encoded SHA-1 thumbprint of the
+// certificate, i.e. its x5t header.
+func (c *Certificate) Thumbprint() string {
+	sum := sha1.Sum(c.Leaf.Raw) //#nosec G401
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// ThumbprintSHA256 returns the base64url-encoded SHA-256 thumbprint of the
+// certificate, i.e. its x5t#S256 header.
+func (c *Certificate) ThumbprintSHA256() string {
+	sum := sha256.Sum256(c.Leaf.Raw)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+type assertionHeader struct {
+	Alg     string `json:"alg"`
+	Typ     string `json:"typ"`
+	X5t     string `json:"x5t"`
+	X5tS256 string `json:"x5t#S256"`
+}
+
+type assertionClaims struct {
+	Aud string `json:"aud"`
+	Iss string `json:"iss"`
+	Sub string `json:"sub"`
+	Jti string `json:"jti"`
+	Nbf int64  `json:"nbf"`
+	Iat int64  `json:"iat"`
+	Exp int64  `json:"exp"`
+}
+
+// assertion returns a client assertion for clientID, signed with RS256, for
+// the token endpoint at aud.
+func (c *Certificate) assertion(clientID, aud string, now time.Time) (string, error) {
+	header := assertionHeader{
+		Alg:     "RS256",
+		Typ:     "JWT",
+		X5t:     c.Thumbprint(),
+		X5tS256: c.ThumbprintSHA256(),
+	}
+	claims := assertionClaims{
+		Aud: aud,
+		Iss: clientID,
+		Sub: clientID,
+		Jti: newGUID(),
+		Nbf: now.Unix(),
+		Iat: now.Unix(),
+		Exp: now.Add(assertionLifetime).Unix(),
+	}
+
+	h, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	cl, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString(h) + "." + enc.EncodeToString(cl)
+
+	digest := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, c.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("rsa.SignPKCS1v15: %v", err)
+	}
+
+	return unsigned + "." + enc.EncodeToString(sig), nil
+}
+
+// assertionTokenSource fetches tokens through the client credentials flow,
+// authenticating with a client assertion (a JWT signed by a certificate or a
+// federated token) fetched anew for each token.
+type assertionTokenSource struct {
+	ctx       context.Context
+	cfg       clientcredentials.Config
+	assertion func() (string, error)
+}
+
+func newAssertionTokenSource(ctx context.Context, cfg clientcredentials.Config, assertion func() (string, error)) oauth2.TokenSource {
+	cfg.ClientSecret = ""
+	cfg.AuthStyle = oauth2.AuthStyleInParams
+
+	return oauth2.ReuseTokenSource(nil, &assertionTokenSource{ctx: ctx, cfg: cfg, assertion: assertion})
+}
+
+func (s *assertionTokenSource) Token() (*oauth2.Token, error) {
+	assertion, err := s.assertion()
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get client assertion: %w", err)
+	}
+
+	cfg := s.cfg
+	cfg.EndpointParams = url.Values{
+		"client_assertion_type": {clientAssertionType},
+		"client_assertion":      {assertion},
+	}
+
+	return cfg.Token(s.ctx)
+}
+
+// signer returns a function signing a new client assertion for the token
+// endpoint of cfg every time it is called.
+func (c *Certificate) signer(cfg clientcredentials.Config) func() (string, error) {
+	return func() (string, error) {
+		return c.assertion(cfg.ClientID, cfg.TokenURL, time.Now())
+	}
+}
@@ -0,0 +1,165 @@
+package graph
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2/clientcredentials"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+func newTestCertificate(t *testing.T) (*x509.Certificate, *rsa.PrivateKey) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "msgraph"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	leaf, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return leaf, key
+}
+
+func TestParseCertificate(t *testing.T) {
+	leaf, key := newTestCertificate(t)
+
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	pemData := append(
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf.Raw}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8})...,
+	)
+
+	pfxData, err := pkcs12.Modern.Encode(key, leaf, nil, "secret")
+	require.NoError(t, err)
+
+	t.Run("pem", func(t *testing.T) {
+		cert, err := ParseCertificate(pemData, "")
+		require.NoError(t, err)
+		require.Equal(t, leaf.Raw, cert.Leaf.Raw)
+	})
+
+	t.Run("pkcs12", func(t *testing.T) {
+		cert, err := ParseCertificate(pfxData, "secret")
+		require.NoError(t, err)
+		require.Equal(t, leaf.Raw, cert.Leaf.Raw)
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		_, err := ParseCertificate(pfxData, "wrong")
+		require.Error(t, err)
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		_, err := ParseCertificate(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf.Raw}), "")
+		require.ErrorContains(t, err, "no private key")
+	})
+
+	t.Run("mismatched key", func(t *testing.T) {
+		_, other := newTestCertificate(t)
+		pkcs1 := x509.MarshalPKCS1PrivateKey(other)
+		data := append(
+			pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf.Raw}),
+			pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: pkcs1})...,
+		)
+
+		_, err := ParseCertificate(data, "")
+		require.ErrorContains(t, err, "doesn't match")
+	})
+}
+
+func TestCertificateAssertion(t *testing.T) {
+	leaf, key := newTestCertificate(t)
+	cert, err := newCertificate(leaf, key)
+	require.NoError(t, err)
+
+	now := time.Unix(1700000000, 0)
+	assertion, err := cert.assertion("client1", "https://login.example/token", now)
+	require.NoError(t, err)
+
+	parts := strings.Split(assertion, ".")
+	require.Len(t, parts, 3)
+
+	var header map[string]string
+	decodeSegment(t, parts[0], &header)
+	sum := sha256.Sum256(leaf.Raw)
+	require.Equal(t, "RS256", header["alg"])
+	require.Equal(t, cert.Thumbprint(), header["x5t"])
+	require.Equal(t, base64.RawURLEncoding.EncodeToString(sum[:]), header["x5t#S256"])
+
+	var claims assertionClaims
+	decodeSegment(t, parts[1], &claims)
+	require.Equal(t, "https://login.example/token", claims.Aud)
+	require.Equal(t, "client1", claims.Iss)
+	require.Equal(t, "client1", claims.Sub)
+	require.NotEmpty(t, claims.Jti)
+	require.Equal(t, now.Add(assertionLifetime).Unix(), claims.Exp)
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	require.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], sig))
+}
+
+func TestAssertionTokenSource(t *testing.T) {
+	leaf, key := newTestCertificate(t)
+	cert, err := newCertificate(leaf, key)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		require.Equal(t, "client1", r.PostForm.Get("client_id"))
+		require.Empty(t, r.PostForm.Get("client_secret"))
+		require.Equal(t, clientAssertionType, r.PostForm.Get("client_assertion_type"))
+		require.NotEmpty(t, r.PostForm.Get("client_assertion"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"token1","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	cfg := clientcredentials.Config{
+		ClientID:     "client1",
+		ClientSecret: "ignored",
+		TokenURL:     server.URL,
+		Scopes:       []string{DefaultScopes},
+	}
+	ts := newAssertionTokenSource(context.Background(), cfg, cert.signer(cfg))
+
+	tok, err := ts.Token()
+	require.NoError(t, err)
+	require.Equal(t, "token1", tok.AccessToken)
+}
+
+func decodeSegment(t *testing.T, seg string, v any) {
+	t.Helper()
+
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, v))
+}
@@ -4,15 +4,21 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"iter"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"os"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/alamo-ds/msgraph/env"
 	"github.com/s-hammon/p"
+	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/clientcredentials"
 	"golang.org/x/time/rate"
 )
@@ -31,6 +37,12 @@ type AzureADConfig struct {
 	TenantID string
 	ClientID string
 	Scopes   []string
+	// CertPath is the path to a PEM or PKCS#12 certificate to authenticate
+	// with instead of a client secret (see NewClientWithCertificate).
+	CertPath string `json:",omitempty"`
+	// Cloud is the name of the national cloud the tenant is in (see
+	// LookupCloud). Empty means the global cloud.
+	Cloud string `json:",omitempty"`
 }
 
 type Client struct {
@@ -39,6 +51,18 @@ type Client struct {
 	ClientID string
 	c        *http.Client
 	limiter  *rate.Limiter
+	retry    RetryPolicy
+
+	// restoreLimit is the limiter's rate before Graph started throttling us,
+	// which throttleTimer restores once the throttling window has passed.
+	restoreLimit  rate.Limit
+	throttleTimer *time.Timer
+	throttleMu    sync.Mutex
+
+	deltaStore DeltaStore
+	logger     *slog.Logger
+	// timeout limits how long each attempt waits for response headers.
+	timeout time.Duration
 
 	eTagCache map[string]string
 	// NOTE: if we expect the eTag to change for a resource, then this can become
@@ -46,41 +70,114 @@ type Client struct {
 	eTagMu sync.RWMutex
 }
 
+// NewClient returns a client which authenticates with clientSecret. It is a
+// shorthand for New with ClientSecretCredential, which doesn't validate the
+// config.
 func NewClient(ctx context.Context, clientSecret string, azureADCfg ...AzureADConfig) *Client {
-	var cfg AzureADConfig
+	return newUnvalidatedClient(ctx, ClientSecretCredential(clientSecret), azureADCfg)
+}
+
+// NewClientWithCertificate returns a client which authenticates with a
+// client assertion signed by cert rather than a client secret. It is a
+// shorthand for New with CertificateCredential, which doesn't validate the
+// config.
+func NewClientWithCertificate(ctx context.Context, cert *Certificate, azureADCfg ...AzureADConfig) *Client {
+	return newUnvalidatedClient(ctx, CertificateCredential(cert), azureADCfg)
+}
+
+// newUnvalidatedClient keeps the behavior of the constructors which predate
+// New: the first of azureADCfg is used, or else config.json, without
+// validation. Since these can't return an error, a malformed config.json or
+// a failing credential fails every request instead.
+func newUnvalidatedClient(ctx context.Context, cred Credential, azureADCfg []AzureADConfig) *Client {
+	o := newOptions(WithCredential(cred))
 	if len(azureADCfg) != 0 {
-		cfg = azureADCfg[0]
-	} else {
-		data := env.LoadConfigFile()
-		json.Unmarshal(data, &cfg)
+		o.cfg = &azureADCfg[0]
+	}
+
+	ctx = o.tokenContext(ctx)
+	cfg, err := o.config()
+
+	var ts oauth2.TokenSource
+	if err == nil {
+		ts, err = cred.TokenSource(ctx, cfg)
 	}
+	if err != nil {
+		ts = errTokenSource{err}
+	}
+
+	return o.build(ctx, cfg, ts)
+}
+
+// errTokenSource fails every request with the error that prevented a client
+// from getting a token source.
+type errTokenSource struct {
+	err error
+}
+
+func (s errTokenSource) Token() (*oauth2.Token, error) {
+	return nil, s.err
+}
+
+// LoadAzureADConfig returns the config stored in config.json by
+// `msgraph set`, with the default scope if none is set. A missing file
+// results in an empty config.
+func LoadAzureADConfig() (AzureADConfig, error) {
+	var cfg AzureADConfig
+
+	if data := env.LoadConfigFile(); len(data) != 0 {
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return cfg.withDefaults(), fmt.Errorf("couldn't parse config.json: %v", err)
+		}
+	}
+
+	return cfg.withDefaults(), nil
+}
+
+// withDefaults fills in the default scope of the cloud, and reads a missing tenant or
+// client ID from the environment.
+func (cfg AzureADConfig) withDefaults() AzureADConfig {
 	if len(cfg.Scopes) == 0 {
-		cfg.Scopes = append(cfg.Scopes, DefaultScopes)
+		cfg.Scopes = []string{cfg.cloud().DefaultScope()}
 	}
+	cfg.TenantID = p.Coalesce(cfg.TenantID, os.Getenv("TENANT_ID"), os.Getenv("AZURE_TENANT_ID"))
+	cfg.ClientID = p.Coalesce(cfg.ClientID, os.Getenv("CLIENT_ID"), os.Getenv("AZURE_CLIENT_ID"))
+
+	return cfg
+}
 
-	eTagCache := env.LoadCacheFile().ETags
-	if eTagCache == nil {
-		eTagCache = make(map[string]string)
-		env.WriteCacheFile("eTag", eTagCache)
+func (cfg AzureADConfig) credentials() clientcredentials.Config {
+	return clientcredentials.Config{
+		ClientID: cfg.ClientID,
+		TokenURL: cfg.endpoint("token"),
+		Scopes:   cfg.Scopes,
 	}
+}
+
+// endpoint returns the URL of an OAuth 2.0 endpoint of the tenant.
+func (cfg AzureADConfig) endpoint(name string) string {
+	return cfg.cloud().AuthorityURL + p.Format("%s/oauth2/v2.0/%s", cfg.TenantID, name)
+}
 
-	adCfg := &clientcredentials.Config{
-		ClientID:     cfg.ClientID,
-		ClientSecret: clientSecret,
-		TokenURL:     DefaultAuthURL + p.Format("%s/oauth2/v2.0/token", cfg.TenantID),
-		Scopes:       cfg.Scopes,
+// cloud returns the cloud of the config, defaulting to CloudGlobal if the
+// name is unknown; New rejects unknown names.
+func (cfg AzureADConfig) cloud() Cloud {
+	c, err := LookupCloud(cfg.Cloud)
+	if err != nil {
+		return CloudGlobal
 	}
 
-	client := &Client{
-		BaseURL:   DefaultBaseURL,
-		TenantID:  cfg.TenantID,
-		ClientID:  cfg.ClientID,
-		c:         adCfg.Client(ctx),
-		limiter:   rate.NewLimiter(rate.Limit(DefaultRequestsPerSecondLimit), DefaultBurst),
-		eTagCache: eTagCache,
+	return c
+}
+
+// log returns the client's logger, which discards everything unless set
+// with WithLogger.
+func (c *Client) log() *slog.Logger {
+	if c.logger == nil {
+		return slog.New(slog.DiscardHandler)
 	}
 
-	return client
+	return c.logger
 }
 
 func (c *Client) Close() {
@@ -106,8 +203,28 @@ func (c *Client) putETag(key, val string) {
 	c.eTagMu.Lock()
 	defer c.eTagMu.Unlock()
 
+	if c.eTagCache == nil {
+		c.eTagCache = make(map[string]string)
+	}
 	c.eTagCache[key] = val
-	log.Println("added eTag to cache w/ value:", val)
+}
+
+// putETagFromBody caches the @odata.etag of a JSON response body, if any.
+func (c *Client) putETagFromBody(key string, data []byte) {
+	var body struct {
+		OdataEtag string `json:"@odata.etag"`
+	}
+
+	if err := json.Unmarshal(data, &body); err == nil && body.OdataEtag != "" {
+		c.putETag(key, body.OdataEtag)
+	}
+}
+
+func (c *Client) deleteETag(key string) {
+	c.eTagMu.Lock()
+	defer c.eTagMu.Unlock()
+
+	delete(c.eTagCache, key)
 }
 
 type refreshETagErr struct {
@@ -118,6 +235,14 @@ func (err refreshETagErr) Error() string {
 	return p.Format("error fetching eTag for resource: %v", err.err)
 }
 
+func (err refreshETagErr) Unwrap() error {
+	if e, ok := err.err.(error); ok {
+		return e
+	}
+
+	return nil
+}
+
 func newRefreshETagErr(err any) refreshETagErr {
 	return refreshETagErr{err: err}
 }
@@ -152,23 +277,19 @@ func (c *Client) refreshETag(ctx context.Context, path string) (string, error) {
 	}
 }
 
-func (c *Client) do(req *http.Request) (*http.Response, error) {
-	if err := c.limiter.Wait(req.Context()); err != nil {
-		return nil, fmt.Errorf("limiter.Wait: %v", err)
-	}
-	// #nosec G704 -- path is internally constructed
-	return c.c.Do(req)
-}
-
-func (c *Client) get(ctx context.Context, path string, body io.Reader) (*http.Response, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, path, body)
+func (c *Client) get(ctx context.Context, path string, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
 	}
 
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
 	resp, err := c.do(req)
 	if err != nil {
-		return nil, fmt.Errorf("client.Do: %v", err)
+		return nil, fmt.Errorf("client.Do: %w", err)
 	}
 	if resp.StatusCode != 200 {
 		return nil, requestErr(resp)
@@ -197,7 +318,10 @@ func (c *Client) patch(ctx context.Context, path string, body io.Reader) (*http.
 
 	resp, err := c.do(req)
 	if err != nil {
-		return nil, fmt.Errorf("client.Do: %v", err)
+		return nil, fmt.Errorf("client.Do: %w", err)
+	}
+	if resp.StatusCode == http.StatusPreconditionFailed {
+		c.deleteETag(path)
 	}
 	if resp.StatusCode != 200 {
 		return nil, requestErr(resp)
@@ -206,6 +330,38 @@ func (c *Client) patch(ctx context.Context, path string, body io.Reader) (*http.
 	return resp, nil
 }
 
+func (c *Client) delete(ctx context.Context, path string) error {
+	eTag, ok := c.getETag(path)
+	if !ok {
+		var err error
+		if eTag, err = c.refreshETag(ctx, path); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("If-Match", eTag)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("client.Do: %w", err)
+	}
+	if resp.StatusCode == http.StatusPreconditionFailed {
+		c.deleteETag(path)
+	}
+	if resp.StatusCode != http.StatusNoContent {
+		return requestErr(resp)
+	}
+	resp.Body.Close()
+
+	c.deleteETag(path)
+	return nil
+}
+
 func (c *Client) post(ctx context.Context, path string, body io.Reader) (*http.Response, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, path, body)
 	if err != nil {
@@ -216,7 +372,7 @@ func (c *Client) post(ctx context.Context, path string, body io.Reader) (*http.R
 
 	resp, err := c.do(req)
 	if err != nil {
-		return nil, fmt.Errorf("client.Do: %v", err)
+		return nil, fmt.Errorf("client.Do: %w", err)
 	}
 	if resp.StatusCode != 201 {
 		return nil, requestErr(resp)
@@ -225,19 +381,55 @@ func (c *Client) post(ctx context.Context, path string, body io.Reader) (*http.R
 	return resp, nil
 }
 
-// Use this only if JoinPath will not throw an error
-func joinPath(base string, elem ...string) string {
-	u, _ := url.JoinPath(base, elem...)
-	return u
+// send sends a request and expects the given status code in response.
+func (c *Client) send(ctx context.Context, method, path string, body io.Reader, want int) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("client.Do: %w", err)
+	}
+	if resp.StatusCode != want {
+		return nil, requestErr(resp)
+	}
+
+	return resp, nil
 }
 
-func readForError(r io.Reader) string {
-	data, err := io.ReadAll(r)
+// sendNoContent sends a request which doesn't return a body, like adding or
+// removing a $ref, and expects 204 No Content.
+func (c *Client) sendNoContent(ctx context.Context, method, path string, body io.Reader) error {
+	resp, err := c.send(ctx, method, path, body, http.StatusNoContent)
 	if err != nil {
-		return "couldn't read body"
+		return err
 	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// betaURL returns the beta API equivalent of a v1.0 base URL.
+func betaURL(base string) string {
+	return strings.TrimSuffix(base, "/v1.0") + "/beta"
+}
 
-	return string(data)
+// betaPath returns the beta API equivalent of a path under the client's
+// base URL.
+func (c *Client) betaPath(path string) string {
+	return betaURL(c.BaseURL) + strings.TrimPrefix(path, c.BaseURL)
+}
+
+// Use this only if JoinPath will not throw an error
+func joinPath(base string, elem ...string) string {
+	u, _ := url.JoinPath(base, elem...)
+	return u
 }
 
 func toBody(v any) io.Reader {
@@ -246,30 +438,140 @@ func toBody(v any) io.Reader {
 	return &buf
 }
 
-func makeReqErr(v any) error {
-	return fmt.Errorf("couldn't create request: %v", v)
+func makeReqErr(err error) error {
+	return fmt.Errorf("couldn't create request: %w", err)
 }
 
+// requestErr converts an unsuccessful response into an *Error.
 func requestErr(resp *http.Response) error {
-	if resp.Body != nil {
-		defer resp.Body.Close()
-		return fmt.Errorf("request returned %d: %s", resp.StatusCode, readForError(resp.Body))
+	if resp.Body == nil {
+		return newError(resp.StatusCode, resp.Header, nil)
 	}
+	defer resp.Body.Close()
 
-	return fmt.Errorf("request returned %d", resp.StatusCode)
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		data = []byte("couldn't read body")
+	}
+
+	return newError(resp.StatusCode, resp.Header, data)
 }
 
 // NOTE: val must be a pointer to a map or struct
-func get[T any](ctx context.Context, c *Client, path string, val T) error {
-	resp, err := c.get(ctx, path, nil)
+func get[T any](ctx context.Context, c *Client, path string, header http.Header, val T) error {
+	resp, err := c.get(ctx, path, header)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if err = json.NewDecoder(resp.Body).Decode(val); err != nil {
+	var data json.RawMessage
+	if err = json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return fmt.Errorf("error decoding response body: %v", err)
+	}
+
+	key, _, _ := strings.Cut(path, "?")
+	c.putETagFromBody(key, data)
+
+	if err = json.Unmarshal(data, val); err != nil {
 		return fmt.Errorf("error decoding response body: %v", err)
 	}
 
 	return nil
 }
+
+// ErrNoMorePages is returned by Pager.Next once the collection is exhausted
+// or the page limit has been reached.
+var ErrNoMorePages = errors.New("no more pages")
+
+// Page is a single page of a Graph collection response.
+type Page[T any] struct {
+	Count    int    `json:"@odata.count"`
+	NextLink string `json:"@odata.nextLink"`
+	Value    []T    `json:"value"`
+}
+
+// Pager fetches a collection one page at a time, following @odata.nextLink
+// until the collection is exhausted or maxPages pages have been fetched.
+type Pager[T any] struct {
+	c        *Client
+	next     string
+	header   http.Header
+	maxPages int
+	pages    int
+}
+
+// NOTE: maxPages <= 0 means there is no page limit
+func newPager[T any](c *Client, path string, header http.Header, maxPages int) *Pager[T] {
+	return &Pager[T]{
+		c:        c,
+		next:     path,
+		header:   header,
+		maxPages: maxPages,
+	}
+}
+
+// More reports whether there is another page to fetch.
+func (p *Pager[T]) More() bool {
+	if p.maxPages > 0 && p.pages >= p.maxPages {
+		return false
+	}
+
+	return p.next != ""
+}
+
+// Next fetches the next page of the collection.
+func (p *Pager[T]) Next(ctx context.Context) (Page[T], error) {
+	var ret Page[T]
+	if !p.More() {
+		return ret, ErrNoMorePages
+	}
+
+	if err := get(ctx, p.c, p.next, p.header, &ret); err != nil {
+		return ret, err
+	}
+	p.next = ret.NextLink
+	p.pages++
+
+	return ret, nil
+}
+
+// All returns an iterator over the remaining items of the collection. Pages
+// are fetched lazily, so breaking out of the loop stops any further requests.
+// If a page can't be fetched, the error is yielded once and iteration stops.
+func (p *Pager[T]) All(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for p.More() {
+			page, err := p.Next(ctx)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, v := range page.Value {
+				if !yield(v, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// getAll collects every item of a collection, following @odata.nextLink for
+// up to maxPages pages (or all of them if maxPages <= 0).
+func getAll[T any](ctx context.Context, c *Client, path string, header http.Header, maxPages int) ([]T, error) {
+	var ret []T
+
+	pager := newPager[T](c, path, header, maxPages)
+	for pager.More() {
+		page, err := pager.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		ret = append(ret, page.Value...)
+	}
+
+	return ret, nil
+}
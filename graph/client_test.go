@@ -108,3 +108,74 @@ func TestNewClientAndClose(t *testing.T) {
 	// Test Close (should write cache file)
 	client.Close()
 }
+
+func TestGetAllFollowsNextLink(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, "/groups", r.URL.Path)
+
+		w.WriteHeader(http.StatusOK)
+		switch r.URL.Query().Get("$skiptoken") {
+		case "":
+			w.Write([]byte(`{"@odata.nextLink":"` + server.URL + `/groups?$skiptoken=page2","value":[{"id":"group1"}]}`))
+		case "page2":
+			w.Write([]byte(`{"@odata.nextLink":"` + server.URL + `/groups?$skiptoken=page3","value":[{"id":"group2"}]}`))
+		case "page3":
+			w.Write([]byte(`{"value":[{"id":"group3"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	client := newClient(server)
+
+	groups, err := client.Groups().Get(context.Background())
+	require.NoError(t, err)
+	require.Len(t, groups, 3)
+	require.Equal(t, "group3", groups[2].ID)
+
+	groups, err = client.Groups().MaxPages(2).Get(context.Background())
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+
+	pager := client.Groups().MaxPages(1).Pages()
+	require.True(t, pager.More())
+
+	page, err := pager.Next(context.Background())
+	require.NoError(t, err)
+	require.Len(t, page.Value, 1)
+	require.NotEmpty(t, page.NextLink)
+	require.False(t, pager.More())
+
+	_, err = pager.Next(context.Background())
+	require.ErrorIs(t, err, ErrNoMorePages)
+}
+
+func TestPagerAllStopsOnBreak(t *testing.T) {
+	var (
+		server   *httptest.Server
+		requests int
+	)
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"@odata.nextLink":"` + server.URL + `/users","value":[{"id":"user1"},{"id":"user2"}]}`))
+	}))
+	defer server.Close()
+
+	client := newClient(server)
+
+	var ids []string
+	for user, err := range client.Users().All(context.Background()) {
+		require.NoError(t, err)
+
+		ids = append(ids, user.ID)
+		if len(ids) == 3 {
+			break
+		}
+	}
+
+	require.Equal(t, []string{"user1", "user2", "user1"}, ids)
+	require.Equal(t, 2, requests)
+}
@@ -0,0 +1,86 @@
+package graph
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Cloud is an Azure environment, with its own Azure AD authority and Graph
+// endpoint. Apps and tenants exist in a single cloud.
+type Cloud struct {
+	Name string
+	// AuthorityURL is the Azure AD endpoint tokens are requested from, with
+	// a trailing slash.
+	AuthorityURL string
+	// GraphURL is the Graph endpoint, without a version.
+	GraphURL string
+}
+
+var (
+	CloudGlobal = Cloud{
+		Name:         "global",
+		AuthorityURL: DefaultAuthURL,
+		GraphURL:     "https://graph.microsoft.com",
+	}
+	// CloudUSGov is the US Government L4 (GCC High) cloud.
+	CloudUSGov = Cloud{
+		Name:         "usgov",
+		AuthorityURL: "https://login.microsoftonline.us/",
+		GraphURL:     "https://graph.microsoft.us",
+	}
+	// CloudUSGovDoD is the US Government L5 (DoD) cloud.
+	CloudUSGovDoD = Cloud{
+		Name:         "usgov-dod",
+		AuthorityURL: "https://login.microsoftonline.us/",
+		GraphURL:     "https://dod-graph.microsoft.us",
+	}
+	// CloudChina is the cloud operated by 21Vianet.
+	CloudChina = Cloud{
+		Name:         "china",
+		AuthorityURL: "https://login.chinacloudapi.cn/",
+		GraphURL:     "https://microsoftgraph.chinacloudapi.cn",
+	}
+
+	// Microsoft Cloud Deutschland isn't listed: it was shut down in 2021,
+	// and its tenants moved to the global cloud.
+	clouds = []Cloud{CloudGlobal, CloudUSGov, CloudUSGovDoD, CloudChina}
+)
+
+// LookupCloud returns the cloud with the given name, case-insensitively. An
+// empty name is CloudGlobal.
+func LookupCloud(name string) (Cloud, error) {
+	if name == "" {
+		return CloudGlobal, nil
+	}
+
+	i := slices.IndexFunc(clouds, func(c Cloud) bool {
+		return strings.EqualFold(c.Name, name)
+	})
+	if i < 0 {
+		return Cloud{}, fmt.Errorf("unknown cloud %q; expected one of %s", name, strings.Join(CloudNames(), ", "))
+	}
+
+	return clouds[i], nil
+}
+
+// CloudNames returns the names accepted by LookupCloud.
+func CloudNames() []string {
+	names := make([]string, len(clouds))
+	for i, c := range clouds {
+		names[i] = c.Name
+	}
+
+	return names
+}
+
+// BaseURL returns the v1.0 Graph endpoint of the cloud.
+func (c Cloud) BaseURL() string {
+	return c.GraphURL + "/v1.0"
+}
+
+// DefaultScope returns the scope requesting every permission granted to the
+// app on the cloud's Graph endpoint.
+func (c Cloud) DefaultScope() string {
+	return c.GraphURL + "/.default"
+}
@@ -0,0 +1,84 @@
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func TestLookupCloud(t *testing.T) {
+	cloud, err := LookupCloud("")
+	require.NoError(t, err)
+	require.Equal(t, CloudGlobal, cloud)
+
+	cloud, err = LookupCloud("USGov")
+	require.NoError(t, err)
+	require.Equal(t, CloudUSGov, cloud)
+
+	_, err = LookupCloud("mars")
+	require.ErrorContains(t, err, "unknown cloud")
+
+	_, err = LookupCloud("germany")
+	require.ErrorContains(t, err, "unknown cloud")
+}
+
+func TestCloudConfig(t *testing.T) {
+	clearConfigEnv(t)
+
+	tests := []struct {
+		cloud     string
+		tokenURL  string
+		scope     string
+		baseURL   string
+		betaUsers string
+	}{
+		{
+			"",
+			"https://login.microsoftonline.com/tenant1/oauth2/v2.0/token",
+			"https://graph.microsoft.com/.default",
+			"https://graph.microsoft.com/v1.0",
+			"https://graph.microsoft.com/beta/users",
+		},
+		{
+			"usgov",
+			"https://login.microsoftonline.us/tenant1/oauth2/v2.0/token",
+			"https://graph.microsoft.us/.default",
+			"https://graph.microsoft.us/v1.0",
+			"https://graph.microsoft.us/beta/users",
+		},
+		{
+			"china",
+			"https://login.chinacloudapi.cn/tenant1/oauth2/v2.0/token",
+			"https://microsoftgraph.chinacloudapi.cn/.default",
+			"https://microsoftgraph.chinacloudapi.cn/v1.0",
+			"https://microsoftgraph.chinacloudapi.cn/beta/users",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cloud, func(t *testing.T) {
+			cfg := AzureADConfig{TenantID: "tenant1", ClientID: "client1", Cloud: tt.cloud}.withDefaults()
+			require.Equal(t, []string{tt.scope}, cfg.Scopes)
+			require.Equal(t, tt.tokenURL, cfg.credentials().TokenURL)
+			require.Equal(t, tt.tokenURL, cfg.delegated().Endpoint.TokenURL)
+
+			client, err := New(context.Background(),
+				WithConfig(cfg),
+				WithTokenSource(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token1"})),
+			)
+			require.NoError(t, err)
+			require.Equal(t, tt.baseURL, client.BaseURL)
+			require.Equal(t, tt.betaUsers, client.betaPath(joinPath(client.BaseURL, "users")))
+		})
+	}
+}
+
+func TestCloudUnknown(t *testing.T) {
+	_, err := New(context.Background(),
+		WithConfig(AzureADConfig{TenantID: "tenant1", ClientID: "client1", Cloud: "mars"}),
+		WithTokenSource(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token1"})),
+	)
+	require.ErrorContains(t, err, "unknown cloud")
+}
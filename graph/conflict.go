@@ -0,0 +1,93 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// ConflictPolicy decides what a Patch does when Graph rejects it with 412
+// Precondition Failed, i.e. the resource changed since its ETag was cached.
+// In every case the cached ETag is refreshed first, so the next Patch won't
+// fail for the same reason.
+type ConflictPolicy int
+
+const (
+	// ConflictFail returns the 412 error.
+	ConflictFail ConflictPolicy = iota
+	// ConflictOverwrite re-sends the same patch with the fresh ETag.
+	ConflictOverwrite
+	// ConflictMerge passes the fresh resource to a merge function, and sends
+	// the patch it returns instead.
+	ConflictMerge
+)
+
+// MergeFunc receives the current state of a resource and the patch that was
+// rejected, and returns the patch to apply instead.
+type MergeFunc[T, P any] func(fresh T, rejected P) (P, error)
+
+// conflictHandler is embedded in request builders which support Patch.
+type conflictHandler[T, P any] struct {
+	policy ConflictPolicy
+	merge  MergeFunc[T, P]
+}
+
+// patchEntity sends params as a PATCH to path, resolving a 412 according to
+// the builder's conflict policy.
+func patchEntity[T, P any](ctx context.Context, c *Client, path string, params P, h conflictHandler[T, P]) (T, error) {
+	var ret T
+
+	resp, err := c.patch(ctx, path, toBody(params))
+	if IsPreconditionFailed(err) {
+		var fresh T
+		if refreshErr := c.refresh(ctx, path, &fresh); refreshErr != nil {
+			return ret, makeReqErr(refreshErr)
+		}
+
+		switch h.policy {
+		default:
+			return ret, makeReqErr(err)
+		case ConflictOverwrite:
+		case ConflictMerge:
+			if h.merge == nil {
+				return ret, makeReqErr(err)
+			}
+			if params, err = h.merge(fresh, params); err != nil {
+				return ret, fmt.Errorf("couldn't merge conflicting changes: %w", err)
+			}
+		}
+
+		resp, err = c.patch(ctx, path, toBody(params))
+	}
+	if err != nil {
+		return ret, makeReqErr(err)
+	}
+
+	if err := handlePatchPostResp(c, path, resp, &ret); err != nil {
+		return ret, err
+	}
+
+	return ret, nil
+}
+
+// refresh fetches the current state of the resource at path into val,
+// caching its ETag along the way.
+func (c *Client) refresh(ctx context.Context, path string, val any) error {
+	resp, err := c.get(ctx, path, nil)
+	if err != nil {
+		return newRefreshETagErr(err)
+	}
+	defer resp.Body.Close()
+
+	var data json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return newRefreshETagErr(err)
+	}
+	c.putETagFromBody(path, data)
+
+	if err := json.Unmarshal(data, val); err != nil {
+		return newRefreshETagErr(err)
+	}
+
+	return nil
+}
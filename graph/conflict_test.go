@@ -0,0 +1,109 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newConflictServer serves a task whose ETag is "fresh", so PATCHes sent
+// with any other ETag fail with 412.
+func newConflictServer(t *testing.T, patches *[]PatchTaskParams) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/planner/tasks/task1", r.URL.Path)
+
+		switch r.Method {
+		case http.MethodGet:
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"@odata.etag":"fresh","id":"task1","title":"Changed in Planner","percentComplete":50}`))
+		case http.MethodPatch:
+			if r.Header.Get("If-Match") != "fresh" {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				w.Write([]byte(`{"error":{"code":"PreconditionFailed","message":"The If-Match header does not match"}}`))
+				return
+			}
+
+			var params PatchTaskParams
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&params))
+			*patches = append(*patches, params)
+
+			title, _ := params.Title.Get()
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"@odata.etag":"newer","id":"task1","title":"` + title + `"}`))
+		}
+	}))
+}
+
+func TestTaskPatchConflict(t *testing.T) {
+	tests := []struct {
+		name    string
+		builder func(*TaskRequestBuilder) *TaskRequestBuilder
+		wantErr bool
+		want    []PatchTaskParams
+	}{
+		{
+			"fail",
+			func(r *TaskRequestBuilder) *TaskRequestBuilder { return r },
+			true,
+			nil,
+		},
+		{
+			"overwrite",
+			func(r *TaskRequestBuilder) *TaskRequestBuilder { return r.OnConflict(ConflictOverwrite) },
+			false,
+			[]PatchTaskParams{{Title: Set("Mine")}},
+		},
+		{
+			"merge",
+			func(r *TaskRequestBuilder) *TaskRequestBuilder {
+				return r.MergeOnConflict(func(fresh Task, rejected PatchTaskParams) (PatchTaskParams, error) {
+					rejected.Title = Set(fresh.Title + " + Mine")
+					return rejected, nil
+				})
+			},
+			false,
+			[]PatchTaskParams{{Title: Set("Changed in Planner + Mine")}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var patches []PatchTaskParams
+			server := newConflictServer(t, &patches)
+			defer server.Close()
+
+			key := server.URL + "/planner/tasks/task1"
+			client := newClient(server)
+			client.eTagCache = map[string]string{key: "stale"}
+
+			_, err := tt.builder(client.Planner().Tasks().ById("task1")).Patch(context.Background(), PatchTaskParams{Title: Set("Mine")})
+			if tt.wantErr {
+				require.True(t, IsPreconditionFailed(err))
+				require.Equal(t, "fresh", client.eTagCache[key])
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, patches)
+			require.Equal(t, "newer", client.eTagCache[key])
+		})
+	}
+}
+
+func TestGetCachesETag(t *testing.T) {
+	server := newTestServer(t, http.MethodGet, "/planner/plans/plan1", `{"@odata.etag":"W/\"plan-etag\"","id":"plan1"}`)
+	defer server.Close()
+
+	client := newClient(server)
+
+	_, err := client.Planner().ById("plan1").Get(context.Background())
+	require.NoError(t, err)
+
+	eTag, ok := client.getETag(server.URL + "/planner/plans/plan1")
+	require.True(t, ok)
+	require.Equal(t, `W/"plan-etag"`, eTag)
+}
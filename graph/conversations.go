@@ -1,31 +1,228 @@
 package graph
 
-import "context"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/http"
+)
+
+const (
+	conversationsResource string = "conversations"
+	postsResource         string = "posts"
+	replyResource         string = "reply"
+	forwardResource       string = "forward"
+)
+
+type ConversationsRequestBuilder struct {
+	GroupId  string
+	c        *Client
+	path     string
+	query    *Query
+	maxPages int
+}
+
+func (r *GroupItemRequestBuilder) Conversations() *ConversationsRequestBuilder {
+	return &ConversationsRequestBuilder{
+		GroupId: r.Id,
+		c:       r.c,
+		path:    joinPath(r.path, conversationsResource),
+	}
+}
+
+// Query sets the OData query options for the request.
+func (r *ConversationsRequestBuilder) Query(q *Query) *ConversationsRequestBuilder {
+	r.query = q
+	return r
+}
+
+// MaxPages limits the number of pages fetched by Get. A value <= 0 (the
+// default) fetches every page.
+func (r *ConversationsRequestBuilder) MaxPages(n int) *ConversationsRequestBuilder {
+	r.maxPages = n
+	return r
+}
+
+func (r *ConversationsRequestBuilder) Get(ctx context.Context) ([]Conversation, error) {
+	return getAll[Conversation](ctx, r.c, r.query.url(r.path), r.query.header(), r.maxPages)
+}
+
+func (r *ConversationsRequestBuilder) Pages() *Pager[Conversation] {
+	return newPager[Conversation](r.c, r.query.url(r.path), r.query.header(), r.maxPages)
+}
+
+// All iterates over every Conversation, see Pager.All.
+func (r *ConversationsRequestBuilder) All(ctx context.Context) iter.Seq2[Conversation, error] {
+	return r.Pages().All(ctx)
+}
+
+// PostConversationParams starts a conversation with a single thread and
+// post.
+type PostConversationParams struct {
+	Topic string
+	Body  ItemBody
+}
+
+type postConversationBody struct {
+	Topic   string               `json:"topic"`
+	Threads []conversationThread `json:"threads"`
+}
+
+type conversationThread struct {
+	Posts []replyPost `json:"posts"`
+}
+
+// Post starts a new conversation in the group.
+func (r *ConversationsRequestBuilder) Post(ctx context.Context, params PostConversationParams) (Conversation, error) {
+	var ret Conversation
+
+	body := postConversationBody{
+		Topic:   params.Topic,
+		Threads: []conversationThread{{Posts: []replyPost{{Body: params.Body}}}},
+	}
+
+	resp, err := r.c.post(ctx, r.path, toBody(body))
+	if err != nil {
+		return ret, err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(&ret); err != nil {
+		return ret, fmt.Errorf("json.Decode: %v", err)
+	}
+
+	return ret, nil
+}
 
 type PostsRequestBuilder struct {
-	Id   string
-	c    *Client
-	path string
+	Id         string
+	c          *Client
+	path       string
+	threadPath string
+	query      *Query
+	maxPages   int
 }
 
 func (r *ThreadsRequestBuilder) ById(id string) *PostsRequestBuilder {
 	return &PostsRequestBuilder{
+		Id:         id,
+		c:          r.c,
+		path:       joinPath(r.path, id, postsResource),
+		threadPath: joinPath(r.path, id),
+	}
+}
+
+// Posts returns r, which already lists the posts of the thread. It lets
+// calls read like the Graph path, e.g. Threads().ById(t).Posts().ById(p).
+func (r *PostsRequestBuilder) Posts() *PostsRequestBuilder {
+	return r
+}
+
+type replyPost struct {
+	Body        ItemBody         `json:"body"`
+	Attachments []FileAttachment `json:"attachments,omitempty"`
+}
+
+type replyParams struct {
+	Post replyPost `json:"post"`
+}
+
+// Reply adds a post to the thread, with optional attachments.
+func (r *PostsRequestBuilder) Reply(ctx context.Context, body ItemBody, attachments ...FileAttachment) error {
+	return reply(ctx, r.c, r.threadPath, body, attachments)
+}
+
+type PostRequestBuilder struct {
+	Id    string
+	c     *Client
+	path  string
+	query *Query
+}
+
+func (r *PostsRequestBuilder) ById(id string) *PostRequestBuilder {
+	return &PostRequestBuilder{
 		Id:   id,
 		c:    r.c,
-		path: joinPath(r.path, id, "posts"),
+		path: joinPath(r.path, id),
 	}
 }
 
-type GetPostsResponse struct {
-	Value []Post `json:"value"`
+// Query sets the OData query options for the request.
+func (r *PostRequestBuilder) Query(q *Query) *PostRequestBuilder {
+	r.query = q
+	return r
 }
 
-func (r *PostsRequestBuilder) Get(ctx context.Context) ([]Post, error) {
-	var ret GetPostsResponse
+func (r *PostRequestBuilder) Get(ctx context.Context) (Post, error) {
+	var ret Post
+
+	if err := get(ctx, r.c, r.query.url(r.path), r.query.header(), &ret); err != nil {
+		return ret, err
+	}
+
+	return ret, nil
+}
+
+// Reply replies to the post in the same thread, with optional attachments.
+func (r *PostRequestBuilder) Reply(ctx context.Context, body ItemBody, attachments ...FileAttachment) error {
+	return reply(ctx, r.c, r.path, body, attachments)
+}
+
+type ForwardPostParams struct {
+	Comment      string      `json:"comment,omitempty"`
+	ToRecipients []Recipient `json:"toRecipients"`
+}
 
-	if err := get(ctx, r.c, r.path, &ret); err != nil {
-		return nil, err
+// Forward forwards the post to the given recipients by email.
+func (r *PostRequestBuilder) Forward(ctx context.Context, params ForwardPostParams) error {
+	resp, err := r.c.send(ctx, http.MethodPost, joinPath(r.path, forwardResource), toBody(params), http.StatusAccepted)
+	if err != nil {
+		return err
 	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// reply posts body to the reply action of a thread or post. Graph accepts
+// the reply and delivers it asynchronously.
+func reply(ctx context.Context, c *Client, path string, body ItemBody, attachments []FileAttachment) error {
+	params := replyParams{Post: replyPost{Body: body, Attachments: attachments}}
+
+	resp, err := c.send(ctx, http.MethodPost, joinPath(path, replyResource), toBody(params), http.StatusAccepted)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// Query sets the OData query options for the request.
+func (r *PostsRequestBuilder) Query(q *Query) *PostsRequestBuilder {
+	r.query = q
+	return r
+}
+
+type GetPostsResponse = Page[Post]
+
+// MaxPages limits the number of pages fetched by Get. A value <= 0 (the
+// default) fetches every page.
+func (r *PostsRequestBuilder) MaxPages(n int) *PostsRequestBuilder {
+	r.maxPages = n
+	return r
+}
+
+func (r *PostsRequestBuilder) Get(ctx context.Context) ([]Post, error) {
+	return getAll[Post](ctx, r.c, r.query.url(r.path), r.query.header(), r.maxPages)
+}
+
+func (r *PostsRequestBuilder) Pages() *Pager[Post] {
+	return newPager[Post](r.c, r.query.url(r.path), r.query.header(), r.maxPages)
+}
 
-	return ret.Value, nil
+// All iterates over every Post, see Pager.All.
+func (r *PostsRequestBuilder) All(ctx context.Context) iter.Seq2[Post, error] {
+	return r.Pages().All(ctx)
 }
@@ -2,7 +2,9 @@ package graph
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -18,3 +20,102 @@ func TestPostsGet(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, posts, 1)
 }
+
+func TestConversationsGet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/groups/group1/conversations", r.URL.Path)
+		require.Equal(t, "id,topic", r.URL.Query().Get("$select"))
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"value":[{"id":"conversation1","topic":"Topic 1"}]}`))
+	}))
+	defer server.Close()
+
+	client := newClient(server)
+
+	conversations, err := client.Groups().ById("group1").Conversations().Query(NewQuery().Select("id", "topic")).Get(context.Background())
+	require.NoError(t, err)
+	require.Len(t, conversations, 1)
+	require.Equal(t, "Topic 1", conversations[0].Topic)
+}
+
+func TestPostGetWithQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/groups/group1/threads/thread1/posts/post1", r.URL.Path)
+		require.Equal(t, "attachments", r.URL.Query().Get("$expand"))
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id":"post1"}`))
+	}))
+	defer server.Close()
+
+	client := newClient(server)
+
+	post, err := client.Groups().ById("group1").Threads().ById("thread1").Posts().ById("post1").Query(NewQuery().Expand("attachments")).Get(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "post1", post.ID)
+}
+
+func TestConversationPost(t *testing.T) {
+	var body map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/groups/group1/conversations", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"conv1","topic":"Status"}`))
+	}))
+	defer server.Close()
+
+	client := newClient(server)
+
+	conv, err := client.Groups().ById("group1").Conversations().Post(context.Background(), PostConversationParams{
+		Topic: "Status",
+		Body:  TextBody("All green"),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "conv1", conv.ID)
+	require.Equal(t, map[string]any{
+		"topic": "Status",
+		"threads": []any{map[string]any{"posts": []any{map[string]any{
+			"body": map[string]any{"contentType": "text", "content": "All green"},
+		}}}},
+	}, body)
+}
+
+func TestThreadAndPostReply(t *testing.T) {
+	var requests []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if r.URL.Path == "/groups/group1/threads/thread1/posts/post1/forward" {
+			require.Equal(t, "FYI", body["comment"])
+		} else {
+			require.Equal(t, map[string]any{"body": map[string]any{"contentType": "html", "content": "<p>Done</p>"}}, body["post"])
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	client := newClient(server)
+	thread := client.Groups().ById("group1").Threads().ById("thread1")
+
+	require.NoError(t, thread.Reply(context.Background(), HTMLBody("<p>Done</p>")))
+	require.NoError(t, thread.Posts().ById("post1").Reply(context.Background(), HTMLBody("<p>Done</p>")))
+	require.NoError(t, thread.Posts().ById("post1").Forward(context.Background(), ForwardPostParams{
+		Comment:      "FYI",
+		ToRecipients: []Recipient{{EmailAddress: EmailAddress{Address: "bob@contoso.com"}}},
+	}))
+
+	require.Equal(t, []string{
+		"/groups/group1/threads/thread1/reply",
+		"/groups/group1/threads/thread1/posts/post1/reply",
+		"/groups/group1/threads/thread1/posts/post1/forward",
+	}, requests)
+}
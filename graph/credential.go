@@ -0,0 +1,160 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/alamo-ds/msgraph/env"
+	"github.com/s-hammon/p"
+	"golang.org/x/oauth2"
+)
+
+// ErrNoCredential is returned by a Credential which isn't configured, e.g.
+// because its environment variables aren't set. ChainCredential skips these.
+var ErrNoCredential = errors.New("no credential found")
+
+// Credential provides the access tokens a client authenticates with.
+type Credential interface {
+	TokenSource(ctx context.Context, cfg AzureADConfig) (oauth2.TokenSource, error)
+}
+
+// CredentialFunc adapts a function to a Credential.
+type CredentialFunc func(ctx context.Context, cfg AzureADConfig) (oauth2.TokenSource, error)
+
+func (f CredentialFunc) TokenSource(ctx context.Context, cfg AzureADConfig) (oauth2.TokenSource, error) {
+	return f(ctx, cfg)
+}
+
+// ClientSecretCredential authenticates the app with a client secret.
+func ClientSecretCredential(secret string) Credential {
+	return CredentialFunc(func(ctx context.Context, cfg AzureADConfig) (oauth2.TokenSource, error) {
+		creds := cfg.credentials()
+		creds.ClientSecret = secret
+		return creds.TokenSource(ctx), nil
+	})
+}
+
+// CertificateCredential authenticates the app with a client assertion signed
+// by cert.
+func CertificateCredential(cert *Certificate) Credential {
+	return CredentialFunc(func(ctx context.Context, cfg AzureADConfig) (oauth2.TokenSource, error) {
+		creds := cfg.credentials()
+		return newAssertionTokenSource(ctx, creds, cert.signer(creds)), nil
+	})
+}
+
+// CertificateFileCredential is a CertificateCredential which loads the
+// certificate from path (see LoadCertificate).
+func CertificateFileCredential(path, password string) Credential {
+	return CredentialFunc(func(ctx context.Context, cfg AzureADConfig) (oauth2.TokenSource, error) {
+		cert, err := LoadCertificate(path, password)
+		if err != nil {
+			return nil, err
+		}
+
+		return CertificateCredential(cert).TokenSource(ctx, cfg)
+	})
+}
+
+// StaticTokenCredential uses a pre-fetched access token, e.g. from
+// `az account get-access-token`. It is never refreshed.
+func StaticTokenCredential(token string) Credential {
+	return CredentialFunc(func(context.Context, AzureADConfig) (oauth2.TokenSource, error) {
+		return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token, TokenType: "Bearer"}), nil
+	})
+}
+
+// WorkloadIdentityCredential authenticates the app with a federated token,
+// e.g. a Kubernetes service account token projected by Azure AD workload
+// identity. The file is read again for every new access token, since the
+// federated token is rotated. An empty tokenFile defaults to
+// AZURE_FEDERATED_TOKEN_FILE.
+func WorkloadIdentityCredential(tokenFile string) Credential {
+	return CredentialFunc(func(ctx context.Context, cfg AzureADConfig) (oauth2.TokenSource, error) {
+		path := p.Coalesce(tokenFile, os.Getenv("AZURE_FEDERATED_TOKEN_FILE"))
+		if path == "" {
+			return nil, fmt.Errorf("%w: AZURE_FEDERATED_TOKEN_FILE not set", ErrNoCredential)
+		}
+
+		return newAssertionTokenSource(ctx, cfg.credentials(), federatedToken(path)), nil
+	})
+}
+
+func federatedToken(path string) func() (string, error) {
+	return func() (string, error) {
+		data, err := os.ReadFile(path) //#nosec G304
+		if err != nil {
+			return "", fmt.Errorf("couldn't read federated token: %w", err)
+		}
+
+		return strings.TrimSpace(string(data)), nil
+	}
+}
+
+// UserCredential acts on behalf of the user signed in with Login, refreshing
+// and saving their tokens as needed.
+func UserCredential() Credential {
+	return CredentialFunc(func(ctx context.Context, cfg AzureADConfig) (oauth2.TokenSource, error) {
+		return newStoredTokenSource(ctx, cfg.delegated(), env.TokenFile{})
+	})
+}
+
+// ChainCredential uses the first of creds which is configured, i.e. which
+// doesn't return ErrNoCredential.
+func ChainCredential(creds ...Credential) Credential {
+	return CredentialFunc(func(ctx context.Context, cfg AzureADConfig) (oauth2.TokenSource, error) {
+		for _, cred := range creds {
+			ts, err := cred.TokenSource(ctx, cfg)
+			if errors.Is(err, ErrNoCredential) {
+				continue
+			}
+
+			return ts, err
+		}
+
+		return nil, ErrNoCredential
+	})
+}
+
+// EnvCredential picks a credential from environment variables, in order:
+//
+//   - GRAPH_ACCESS_TOKEN: StaticTokenCredential
+//   - CLIENT_SECRET or AZURE_CLIENT_SECRET: ClientSecretCredential
+//   - CLIENT_CERT_PATH or AZURE_CLIENT_CERTIFICATE_PATH, with the password in
+//     CLIENT_CERT_PASSWORD or AZURE_CLIENT_CERTIFICATE_PASSWORD:
+//     CertificateFileCredential
+//   - AZURE_FEDERATED_TOKEN_FILE: WorkloadIdentityCredential
+//
+// so the same code runs in CI, locally and in Kubernetes.
+func EnvCredential() Credential {
+	return ChainCredential(
+		envCredential(func(val string) Credential {
+			return StaticTokenCredential(val)
+		}, "GRAPH_ACCESS_TOKEN"),
+		envCredential(func(val string) Credential {
+			return ClientSecretCredential(val)
+		}, "CLIENT_SECRET", "AZURE_CLIENT_SECRET"),
+		envCredential(func(val string) Credential {
+			password := p.Coalesce(os.Getenv("CLIENT_CERT_PASSWORD"), os.Getenv("AZURE_CLIENT_CERTIFICATE_PASSWORD"))
+			return CertificateFileCredential(val, password)
+		}, "CLIENT_CERT_PATH", "AZURE_CLIENT_CERTIFICATE_PATH"),
+		WorkloadIdentityCredential(""),
+	)
+}
+
+// envCredential builds a credential from the first of keys set in the
+// environment, or returns ErrNoCredential if none are.
+func envCredential(build func(val string) Credential, keys ...string) Credential {
+	return CredentialFunc(func(ctx context.Context, cfg AzureADConfig) (oauth2.TokenSource, error) {
+		for _, key := range keys {
+			if val := os.Getenv(key); val != "" {
+				return build(val).TokenSource(ctx, cfg)
+			}
+		}
+
+		return nil, fmt.Errorf("%w: %s not set", ErrNoCredential, keys[0])
+	})
+}
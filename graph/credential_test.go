@@ -0,0 +1,121 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func clearCredentialEnv(t *testing.T) {
+	t.Helper()
+
+	for _, key := range []string{
+		"GRAPH_ACCESS_TOKEN",
+		"CLIENT_SECRET",
+		"AZURE_CLIENT_SECRET",
+		"CLIENT_CERT_PATH",
+		"AZURE_CLIENT_CERTIFICATE_PATH",
+		"AZURE_FEDERATED_TOKEN_FILE",
+	} {
+		t.Setenv(key, "")
+	}
+}
+
+func TestEnvCredential(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("none", func(t *testing.T) {
+		clearCredentialEnv(t)
+
+		_, err := EnvCredential().TokenSource(ctx, AzureADConfig{})
+		require.ErrorIs(t, err, ErrNoCredential)
+	})
+
+	t.Run("static token", func(t *testing.T) {
+		clearCredentialEnv(t)
+		t.Setenv("GRAPH_ACCESS_TOKEN", "token1")
+		t.Setenv("CLIENT_SECRET", "secret")
+
+		ts, err := EnvCredential().TokenSource(ctx, AzureADConfig{})
+		require.NoError(t, err)
+
+		tok, err := ts.Token()
+		require.NoError(t, err)
+		require.Equal(t, "token1", tok.AccessToken)
+	})
+
+	t.Run("missing certificate", func(t *testing.T) {
+		clearCredentialEnv(t)
+		t.Setenv("AZURE_CLIENT_CERTIFICATE_PATH", filepath.Join(t.TempDir(), "missing.pem"))
+
+		_, err := EnvCredential().TokenSource(ctx, AzureADConfig{})
+		require.Error(t, err)
+		require.NotErrorIs(t, err, ErrNoCredential)
+	})
+}
+
+func TestChainCredential(t *testing.T) {
+	errBroken := errors.New("broken")
+	none := CredentialFunc(func(context.Context, AzureADConfig) (oauth2.TokenSource, error) {
+		return nil, ErrNoCredential
+	})
+	broken := CredentialFunc(func(context.Context, AzureADConfig) (oauth2.TokenSource, error) {
+		return nil, errBroken
+	})
+
+	ts, err := ChainCredential(none, StaticTokenCredential("token1"), broken).TokenSource(context.Background(), AzureADConfig{})
+	require.NoError(t, err)
+	tok, err := ts.Token()
+	require.NoError(t, err)
+	require.Equal(t, "token1", tok.AccessToken)
+
+	_, err = ChainCredential(none, broken).TokenSource(context.Background(), AzureADConfig{})
+	require.ErrorIs(t, err, errBroken)
+
+	_, err = ChainCredential(none).TokenSource(context.Background(), AzureADConfig{})
+	require.ErrorIs(t, err, ErrNoCredential)
+}
+
+func TestWorkloadIdentityCredential(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("federated1"), 0600))
+
+	var assertions []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, clientAssertionType, r.PostForm.Get("client_assertion_type"))
+		assertions = append(assertions, r.PostForm.Get("client_assertion"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"token1","token_type":"Bearer","expires_in":1}`))
+	}))
+	defer server.Close()
+
+	cfg := AzureADConfig{ClientID: "client1"}.credentials()
+	cfg.TokenURL = server.URL
+
+	_, err := WorkloadIdentityCredential(tokenFile).TokenSource(context.Background(), AzureADConfig{})
+	require.NoError(t, err)
+
+	// the federated token is rotated, so it's read again for every token
+	ts := newAssertionTokenSource(context.Background(), cfg, federatedToken(tokenFile))
+	_, err = ts.Token()
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(tokenFile, []byte("federated2"), 0600))
+	_, err = ts.Token()
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"federated1", "federated2"}, assertions)
+
+	t.Setenv("AZURE_FEDERATED_TOKEN_FILE", "")
+	_, err = WorkloadIdentityCredential("").TokenSource(context.Background(), AzureADConfig{})
+	require.ErrorIs(t, err, ErrNoCredential)
+}
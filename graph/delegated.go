@@ -0,0 +1,139 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+
+	"github.com/alamo-ds/msgraph/env"
+	"golang.org/x/oauth2"
+)
+
+// offlineAccessScope asks for a refresh token along with the access token.
+const offlineAccessScope = "offline_access"
+
+var ErrNotLoggedIn = fmt.Errorf("%w: not logged in", ErrNoCredential)
+
+// TokenStore persists the signed-in user's access and refresh tokens between
+// runs. Login and NewDelegatedClient use env.TokenFile, which stores them in
+// token.json.
+type TokenStore interface {
+	Load() (tok *oauth2.Token, ok bool, err error)
+	Save(tok *oauth2.Token) error
+	Delete() error
+}
+
+// Login signs a user in through the device code flow: it writes the
+// verification URL and code to w, waits for the user to enter the code in a
+// browser and saves the resulting tokens for NewDelegatedClient. The app
+// registration must allow public client flows.
+func Login(ctx context.Context, w io.Writer, azureADCfg ...AzureADConfig) error {
+	o := newOptions()
+	if len(azureADCfg) != 0 {
+		o.cfg = &azureADCfg[0]
+	}
+
+	cfg, err := o.config()
+	if err != nil {
+		return err
+	}
+
+	return deviceLogin(ctx, w, cfg.delegated(), env.TokenFile{})
+}
+
+// Logout deletes the tokens saved by Login.
+func Logout() error {
+	return env.TokenFile{}.Delete()
+}
+
+// NewDelegatedClient returns a client which acts on behalf of the user
+// signed in with Login, so /me and other user-scoped endpoints are
+// available. The access token is refreshed, and saved again, whenever it
+// expires. Returns ErrNotLoggedIn if no one has signed in.
+func NewDelegatedClient(ctx context.Context, azureADCfg ...AzureADConfig) (*Client, error) {
+	opts := []Option{WithCredential(UserCredential())}
+	if len(azureADCfg) != 0 {
+		opts = append(opts, WithConfig(azureADCfg[0]))
+	}
+
+	return New(ctx, opts...)
+}
+
+func (cfg AzureADConfig) delegated() *oauth2.Config {
+	scopes := slices.Clone(cfg.Scopes)
+	if !slices.Contains(scopes, offlineAccessScope) {
+		scopes = append(scopes, offlineAccessScope)
+	}
+
+	return &oauth2.Config{
+		ClientID: cfg.ClientID,
+		Endpoint: oauth2.Endpoint{
+			DeviceAuthURL: cfg.endpoint("devicecode"),
+			TokenURL:      cfg.endpoint("token"),
+			AuthStyle:     oauth2.AuthStyleInParams,
+		},
+		Scopes: scopes,
+	}
+}
+
+func deviceLogin(ctx context.Context, w io.Writer, cfg *oauth2.Config, store TokenStore) error {
+	resp, err := cfg.DeviceAuth(ctx)
+	if err != nil {
+		return fmt.Errorf("couldn't start device login: %w", err)
+	}
+
+	fmt.Fprintf(w, "To sign in, open %s and enter the code %s\n", resp.VerificationURI, resp.UserCode)
+
+	tok, err := cfg.DeviceAccessToken(ctx, resp)
+	if err != nil {
+		return fmt.Errorf("couldn't complete device login: %w", err)
+	}
+
+	return store.Save(tok)
+}
+
+// storedTokenSource saves the token to its store every time it is
+// refreshed.
+type storedTokenSource struct {
+	src   oauth2.TokenSource
+	store TokenStore
+
+	mu   sync.Mutex
+	last string
+}
+
+func newStoredTokenSource(ctx context.Context, cfg *oauth2.Config, store TokenStore) (oauth2.TokenSource, error) {
+	tok, ok, err := store.Load()
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, ErrNotLoggedIn
+	}
+
+	return &storedTokenSource{
+		src:   cfg.TokenSource(ctx, tok),
+		store: store,
+		last:  tok.AccessToken,
+	}, nil
+}
+
+func (s *storedTokenSource) Token() (*oauth2.Token, error) {
+	tok, err := s.src.Token()
+	if err != nil {
+		return nil, fmt.Errorf("couldn't refresh token, sign in again: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if tok.AccessToken != s.last {
+		if err := s.store.Save(tok); err != nil {
+			return nil, err
+		}
+		s.last = tok.AccessToken
+	}
+
+	return tok, nil
+}
@@ -0,0 +1,120 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+type memTokenStore struct {
+	tok   *oauth2.Token
+	saves int
+}
+
+func (s *memTokenStore) Load() (*oauth2.Token, bool, error) {
+	return s.tok, s.tok != nil, nil
+}
+
+func (s *memTokenStore) Save(tok *oauth2.Token) error {
+	s.tok = tok
+	s.saves++
+	return nil
+}
+
+func (s *memTokenStore) Delete() error {
+	s.tok = nil
+	return nil
+}
+
+func newAuthServer(t *testing.T) (*httptest.Server, *oauth2.Config) {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "client1", r.PostForm.Get("client_id"))
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/devicecode":
+			require.Equal(t, "https://graph.microsoft.com/.default offline_access", r.PostForm.Get("scope"))
+			w.Write([]byte(`{"device_code":"dc1","user_code":"ABCD1234","verification_uri":"https://microsoft.com/devicelogin","expires_in":900,"interval":1}`))
+		case "/token":
+			switch r.PostForm.Get("grant_type") {
+			case "urn:ietf:params:oauth:grant-type:device_code":
+				require.Equal(t, "dc1", r.PostForm.Get("device_code"))
+				w.Write([]byte(`{"access_token":"access1","refresh_token":"refresh1","token_type":"Bearer","expires_in":3600}`))
+			case "refresh_token":
+				require.Equal(t, "refresh1", r.PostForm.Get("refresh_token"))
+				w.Write([]byte(`{"access_token":"access2","refresh_token":"refresh2","token_type":"Bearer","expires_in":3600}`))
+			default:
+				t.Errorf("unexpected grant_type %q", r.PostForm.Get("grant_type"))
+			}
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+
+	cfg := AzureADConfig{ClientID: "client1", Scopes: []string{DefaultScopes}}.delegated()
+	cfg.Endpoint.DeviceAuthURL = server.URL + "/devicecode"
+	cfg.Endpoint.TokenURL = server.URL + "/token"
+
+	return server, cfg
+}
+
+func TestDeviceLogin(t *testing.T) {
+	server, cfg := newAuthServer(t)
+	defer server.Close()
+
+	var (
+		out   bytes.Buffer
+		store memTokenStore
+	)
+	require.NoError(t, deviceLogin(context.Background(), &out, cfg, &store))
+
+	require.Contains(t, out.String(), "https://microsoft.com/devicelogin")
+	require.Contains(t, out.String(), "ABCD1234")
+	require.Equal(t, "access1", store.tok.AccessToken)
+	require.Equal(t, "refresh1", store.tok.RefreshToken)
+}
+
+func TestStoredTokenSource(t *testing.T) {
+	server, cfg := newAuthServer(t)
+	defer server.Close()
+
+	t.Run("not logged in", func(t *testing.T) {
+		_, err := newStoredTokenSource(context.Background(), cfg, &memTokenStore{})
+		require.ErrorIs(t, err, ErrNotLoggedIn)
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		store := &memTokenStore{tok: &oauth2.Token{AccessToken: "access1", RefreshToken: "refresh1", Expiry: time.Now().Add(time.Hour)}}
+		ts, err := newStoredTokenSource(context.Background(), cfg, store)
+		require.NoError(t, err)
+
+		tok, err := ts.Token()
+		require.NoError(t, err)
+		require.Equal(t, "access1", tok.AccessToken)
+		require.Zero(t, store.saves)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		store := &memTokenStore{tok: &oauth2.Token{AccessToken: "access1", RefreshToken: "refresh1", Expiry: time.Now().Add(-time.Hour)}}
+		ts, err := newStoredTokenSource(context.Background(), cfg, store)
+		require.NoError(t, err)
+
+		tok, err := ts.Token()
+		require.NoError(t, err)
+		require.Equal(t, "access2", tok.AccessToken)
+		require.Equal(t, "refresh2", store.tok.RefreshToken)
+
+		_, err = ts.Token()
+		require.NoError(t, err)
+		require.Equal(t, 1, store.saves)
+	})
+}
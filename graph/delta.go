@@ -0,0 +1,244 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const deltaResource string = "delta"
+
+// DeltaStore persists the delta link returned at the end of a delta query,
+// so the next query only returns what changed since. Keys are the URL of the
+// initial delta request, including its query, as different queries on the
+// same resource track changes separately.
+type DeltaStore interface {
+	Load(key string) (link string, ok bool, err error)
+	Save(key, link string) error
+}
+
+// WithDeltaStore replaces where delta links are persisted. NewClient uses
+// env.DeltaLinkCache, which stores them in cache.json.
+func (c *Client) WithDeltaStore(store DeltaStore) *Client {
+	c.deltaStore = store
+	return c
+}
+
+// DeltaItem is a single change returned by a delta query. If Removed is
+// true, only ID is set and Reason is either "changed" (e.g. soft-deleted)
+// or "deleted".
+type DeltaItem[T any] struct {
+	ID      string
+	Value   T
+	Removed bool
+	Reason  string
+}
+
+// DeltaResult holds every change since the previous delta query, along with
+// the delta link to resume from next time.
+type DeltaResult[T any] struct {
+	Items     []DeltaItem[T]
+	DeltaLink string
+}
+
+type deltaPage struct {
+	NextLink  string            `json:"@odata.nextLink"`
+	DeltaLink string            `json:"@odata.deltaLink"`
+	Value     []json.RawMessage `json:"value"`
+}
+
+type deltaEntry struct {
+	ID      string `json:"id"`
+	Removed *struct {
+		Reason string `json:"reason"`
+	} `json:"@removed"`
+}
+
+type DeltaRequestBuilder[T any] struct {
+	c     *Client
+	path  string
+	query *Query
+	store DeltaStore
+	from  string
+}
+
+func newDeltaRequestBuilder[T any](c *Client, path string) *DeltaRequestBuilder[T] {
+	return &DeltaRequestBuilder[T]{
+		c:     c,
+		path:  joinPath(path, deltaResource),
+		store: c.deltaStore,
+	}
+}
+
+func (r *GroupsRequestBuilder) Delta() *DeltaRequestBuilder[Group] {
+	return newDeltaRequestBuilder[Group](r.c, r.path)
+}
+
+func (r *UsersRequestBuilder) Delta() *DeltaRequestBuilder[User] {
+	return newDeltaRequestBuilder[User](r.c, r.path)
+}
+
+// PlannerDelta tracks changes to the plans, buckets and tasks the user can
+// access. NOTE: this endpoint is only available on the beta API.
+func (r *UserRequestBuilder) PlannerDelta() *DeltaRequestBuilder[PlannerDeltaItem] {
+	path := joinPath(r.c.betaPath(r.path), plannerResource, "all")
+	return newDeltaRequestBuilder[PlannerDeltaItem](r.c, path)
+}
+
+// PlannerDeltaItem is a changed Planner object, whose concrete type is
+// given by OdataType.
+type PlannerDeltaItem struct {
+	OdataType string `json:"@odata.type"`
+	ID        string `json:"id"`
+	raw       json.RawMessage
+}
+
+func (i *PlannerDeltaItem) UnmarshalJSON(data []byte) error {
+	type alias PlannerDeltaItem
+
+	var v alias
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*i = PlannerDeltaItem(v)
+	i.raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+func (i PlannerDeltaItem) Task() (Task, bool) {
+	return decodePlannerDeltaItem[Task](i, "#microsoft.graph.plannerTask")
+}
+
+func (i PlannerDeltaItem) Bucket() (Bucket, bool) {
+	return decodePlannerDeltaItem[Bucket](i, "#microsoft.graph.plannerBucket")
+}
+
+func (i PlannerDeltaItem) Plan() (Plan, bool) {
+	return decodePlannerDeltaItem[Plan](i, "#microsoft.graph.plannerPlan")
+}
+
+func decodePlannerDeltaItem[T any](i PlannerDeltaItem, odataType string) (T, bool) {
+	var ret T
+	if i.OdataType != odataType {
+		return ret, false
+	}
+
+	return ret, json.Unmarshal(i.raw, &ret) == nil
+}
+
+// Query sets the OData query options for the initial delta request. Graph
+// carries these over into the next and delta links.
+func (r *DeltaRequestBuilder[T]) Query(q *Query) *DeltaRequestBuilder[T] {
+	r.query = q
+	return r
+}
+
+// Store overrides the client's DeltaStore for this query. A nil store means
+// delta links are neither loaded nor saved.
+func (r *DeltaRequestBuilder[T]) Store(store DeltaStore) *DeltaRequestBuilder[T] {
+	r.store = store
+	return r
+}
+
+// From resumes from the given delta link instead of the stored one.
+func (r *DeltaRequestBuilder[T]) From(deltaLink string) *DeltaRequestBuilder[T] {
+	r.from = deltaLink
+	return r
+}
+
+// FromToken resumes from a $deltatoken, e.g. one returned by DeltaToken.
+func (r *DeltaRequestBuilder[T]) FromToken(token string) *DeltaRequestBuilder[T] {
+	r.from = r.path + "?$deltatoken=" + url.QueryEscape(token)
+	return r
+}
+
+// Get follows @odata.nextLink until Graph returns an @odata.deltaLink, and
+// returns every change along the way. The first query for a resource
+// returns its full state. The delta link is only saved once every page has
+// been fetched, so an interrupted query is simply repeated next time.
+func (r *DeltaRequestBuilder[T]) Get(ctx context.Context) (DeltaResult[T], error) {
+	var ret DeltaResult[T]
+
+	key := r.query.url(r.path)
+	next := r.from
+	if next == "" && r.store != nil {
+		link, ok, err := r.store.Load(key)
+		if err != nil {
+			return ret, fmt.Errorf("couldn't load delta link: %v", err)
+		}
+		if ok {
+			next = link
+		}
+	}
+	if next == "" {
+		next = key
+	}
+
+	for next != "" {
+		var page deltaPage
+		if err := get(ctx, r.c, next, r.query.header(), &page); err != nil {
+			return ret, err
+		}
+
+		for _, raw := range page.Value {
+			item, err := decodeDeltaItem[T](raw)
+			if err != nil {
+				return ret, err
+			}
+			ret.Items = append(ret.Items, item)
+		}
+
+		next = page.NextLink
+		ret.DeltaLink = page.DeltaLink
+	}
+
+	if r.store != nil && ret.DeltaLink != "" {
+		if err := r.store.Save(key, ret.DeltaLink); err != nil {
+			return ret, fmt.Errorf("couldn't save delta link: %v", err)
+		}
+	}
+
+	return ret, nil
+}
+
+// DeltaToken returns the $deltatoken (or $skiptoken) of a delta link.
+func DeltaToken(deltaLink string) string {
+	for _, key := range []string{"$deltatoken=", "$skiptoken="} {
+		if _, token, ok := strings.Cut(deltaLink, key); ok {
+			token, _, _ = strings.Cut(token, "&")
+			if unescaped, err := url.QueryUnescape(token); err == nil {
+				return unescaped
+			}
+			return token
+		}
+	}
+
+	return ""
+}
+
+func decodeDeltaItem[T any](raw json.RawMessage) (DeltaItem[T], error) {
+	var (
+		ret   DeltaItem[T]
+		entry deltaEntry
+	)
+
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return ret, fmt.Errorf("error decoding delta item: %v", err)
+	}
+	ret.ID = entry.ID
+
+	if entry.Removed != nil {
+		ret.Removed = true
+		ret.Reason = entry.Removed.Reason
+		return ret, nil
+	}
+
+	if err := json.Unmarshal(raw, &ret.Value); err != nil {
+		return ret, fmt.Errorf("error decoding delta item: %v", err)
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,124 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type memDeltaStore map[string]string
+
+func (s memDeltaStore) Load(key string) (string, bool, error) {
+	link, ok := s[key]
+	return link, ok, nil
+}
+
+func (s memDeltaStore) Save(key, link string) error {
+	s[key] = link
+	return nil
+}
+
+func TestUsersDelta(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/users/delta", r.URL.Path)
+
+		w.WriteHeader(http.StatusOK)
+		switch {
+		case r.URL.Query().Get("$deltatoken") == "token2":
+			w.Write([]byte(`{"@odata.deltaLink":"` + server.URL + `/users/delta?$deltatoken=token3","value":[{"id":"user1","@removed":{"reason":"deleted"}}]}`))
+		case r.URL.Query().Get("$skiptoken") == "page2":
+			w.Write([]byte(`{"@odata.deltaLink":"` + server.URL + `/users/delta?$deltatoken=token2","value":[{"id":"user2","displayName":"User 2"}]}`))
+		default:
+			require.Equal(t, "id,displayName", r.URL.Query().Get("$select"))
+			w.Write([]byte(`{"@odata.nextLink":"` + server.URL + `/users/delta?$skiptoken=page2","value":[{"id":"user1","displayName":"User 1"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	store := make(memDeltaStore)
+	client := newClient(server).WithDeltaStore(store)
+	key := server.URL + "/users/delta?$select=id%2CdisplayName"
+
+	delta, err := client.Users().Delta().Query(NewQuery().Select("id", "displayName")).Get(context.Background())
+	require.NoError(t, err)
+	require.Len(t, delta.Items, 2)
+	require.Equal(t, "User 2", delta.Items[1].Value.DisplayName)
+	require.Equal(t, "token2", DeltaToken(delta.DeltaLink))
+	require.Equal(t, delta.DeltaLink, store[key])
+
+	// resumes from the stored delta link
+	delta, err = client.Users().Delta().Query(NewQuery().Select("id", "displayName")).Get(context.Background())
+	require.NoError(t, err)
+	require.Len(t, delta.Items, 1)
+	require.True(t, delta.Items[0].Removed)
+	require.Equal(t, "deleted", delta.Items[0].Reason)
+	require.Equal(t, "user1", delta.Items[0].ID)
+
+	// resumes from an explicit token, without touching the store
+	delta, err = client.Users().Delta().Store(nil).FromToken("token2").Get(context.Background())
+	require.NoError(t, err)
+	require.Len(t, delta.Items, 1)
+	require.Equal(t, "token3", DeltaToken(store[key]))
+}
+
+func TestDeltaStoreKeyIncludesQuery(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/users/delta", r.URL.Path)
+
+		// the delta token is named after the query it continues
+		token := r.URL.Query().Get("$select")
+		if deltaToken := r.URL.Query().Get("$deltatoken"); deltaToken != "" {
+			token = deltaToken + "2"
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"@odata.deltaLink":"` + server.URL + `/users/delta?$deltatoken=` + token + `","value":[]}`))
+	}))
+	defer server.Close()
+
+	store := make(memDeltaStore)
+	client := newClient(server).WithDeltaStore(store)
+
+	for _, field := range []string{"id", "displayName", "id"} {
+		_, err := client.Users().Delta().Query(NewQuery().Select(field)).Get(context.Background())
+		require.NoError(t, err)
+	}
+
+	require.Len(t, store, 2)
+	require.Equal(t, "id2", DeltaToken(store[server.URL+"/users/delta?$select=id"]))
+	require.Equal(t, "displayName", DeltaToken(store[server.URL+"/users/delta?$select=displayName"]))
+}
+
+func TestPlannerDeltaItem(t *testing.T) {
+	server := newTestServer(t, http.MethodGet, "/beta/users/user1/planner/all/delta", `{
+		"@odata.deltaLink": "https://graph.microsoft.com/beta/users/user1/planner/all/delta?$deltatoken=abc",
+		"value": [
+			{"@odata.type":"#microsoft.graph.plannerTask","id":"task1","title":"Task 1"},
+			{"@odata.type":"#microsoft.graph.plannerBucket","id":"bucket1","name":"Bucket 1"}
+		]
+	}`)
+	defer server.Close()
+
+	client := newClient(server)
+	client.BaseURL = server.URL + "/v1.0"
+
+	delta, err := client.Users().ById("user1").PlannerDelta().Get(context.Background())
+	require.NoError(t, err)
+	require.Len(t, delta.Items, 2)
+
+	task, ok := delta.Items[0].Value.Task()
+	require.True(t, ok)
+	require.Equal(t, "Task 1", task.Title)
+
+	_, ok = delta.Items[1].Value.Task()
+	require.False(t, ok)
+
+	bucket, ok := delta.Items[1].Value.Bucket()
+	require.True(t, ok)
+	require.Equal(t, "Bucket 1", bucket.Name)
+}
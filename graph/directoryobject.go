@@ -0,0 +1,72 @@
+package graph
+
+import "encoding/json"
+
+const (
+	directoryObjectsResource string = "directoryObjects"
+
+	userType             string = "#microsoft.graph.user"
+	groupType            string = "#microsoft.graph.group"
+	servicePrincipalType string = "#microsoft.graph.servicePrincipal"
+)
+
+// DirectoryObject is a member or owner of a group, whose concrete type is
+// given by OdataType.
+type DirectoryObject struct {
+	OdataType   string `json:"@odata.type"`
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName"`
+	raw         json.RawMessage
+}
+
+func (o *DirectoryObject) UnmarshalJSON(data []byte) error {
+	type alias DirectoryObject
+
+	var v alias
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*o = DirectoryObject(v)
+	o.raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+func (o DirectoryObject) MarshalJSON() ([]byte, error) {
+	if o.raw != nil {
+		return o.raw, nil
+	}
+
+	type alias DirectoryObject
+	return json.Marshal(alias(o))
+}
+
+func (o DirectoryObject) User() (User, bool) {
+	return decodeDirectoryObject[User](o, userType)
+}
+
+func (o DirectoryObject) Group() (Group, bool) {
+	return decodeDirectoryObject[Group](o, groupType)
+}
+
+func (o DirectoryObject) ServicePrincipal() (ServicePrincipal, bool) {
+	return decodeDirectoryObject[ServicePrincipal](o, servicePrincipalType)
+}
+
+func decodeDirectoryObject[T any](o DirectoryObject, odataType string) (T, bool) {
+	var ret T
+	if o.OdataType != odataType {
+		return ret, false
+	}
+
+	return ret, json.Unmarshal(o.raw, &ret) == nil
+}
+
+type ServicePrincipal struct {
+	ID                   string   `json:"id"`
+	AppID                string   `json:"appId"`
+	DisplayName          string   `json:"displayName"`
+	ServicePrincipalType string   `json:"servicePrincipalType"`
+	AccountEnabled       bool     `json:"accountEnabled"`
+	Tags                 []string `json:"tags"`
+}
@@ -0,0 +1,150 @@
+package graph
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/s-hammon/p"
+)
+
+var (
+	ErrBadRequest         = errors.New("bad request")
+	ErrUnauthorized       = errors.New("unauthorized")
+	ErrForbidden          = errors.New("forbidden")
+	ErrNotFound           = errors.New("resource not found")
+	ErrConflict           = errors.New("conflict")
+	ErrPreconditionFailed = errors.New("precondition failed")
+	ErrThrottled          = errors.New("request throttled")
+)
+
+// Error is the error returned for any unsuccessful Graph response. It can be
+// matched against the sentinel errors above with errors.Is, e.g.
+//
+//	if errors.Is(err, graph.ErrNotFound) {
+//	    ...
+//	}
+//
+// or inspected further with errors.As.
+type Error struct {
+	StatusCode      int
+	Code            string
+	Message         string
+	RequestID       string
+	ClientRequestID string
+	Date            string
+	Target          string
+	Details         []ErrorDetail
+	InnerError      *InnerError
+}
+
+type ErrorDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Target  string `json:"target"`
+}
+
+type InnerError struct {
+	Code            string      `json:"code"`
+	Date            string      `json:"date"`
+	RequestID       string      `json:"request-id"`
+	ClientRequestID string      `json:"client-request-id"`
+	InnerError      *InnerError `json:"innerError"`
+}
+
+// errorResponse mirrors the JSON payload Graph sends on errors.
+type errorResponse struct {
+	Error struct {
+		Code       string        `json:"code"`
+		Message    string        `json:"message"`
+		Target     string        `json:"target"`
+		Details    []ErrorDetail `json:"details"`
+		InnerError *InnerError   `json:"innerError"`
+	} `json:"error"`
+}
+
+func (e *Error) Error() string {
+	var sb strings.Builder
+
+	sb.WriteString(p.Format("request returned %d", e.StatusCode))
+	if e.Code != "" {
+		sb.WriteString(p.Format(" (%s)", e.Code))
+	}
+	if e.Message != "" {
+		sb.WriteString(": " + e.Message)
+	}
+	if e.RequestID != "" {
+		sb.WriteString(p.Format(" [request-id: %s]", e.RequestID))
+	}
+
+	return sb.String()
+}
+
+// Is matches e against the sentinel errors by HTTP status.
+func (e *Error) Is(target error) bool {
+	switch target {
+	default:
+		return false
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrPreconditionFailed:
+		return e.StatusCode == http.StatusPreconditionFailed
+	case ErrThrottled:
+		return e.StatusCode == http.StatusTooManyRequests
+	}
+}
+
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+func IsThrottled(err error) bool {
+	return errors.Is(err, ErrThrottled)
+}
+
+func IsPreconditionFailed(err error) bool {
+	return errors.Is(err, ErrPreconditionFailed)
+}
+
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// newError builds an *Error from a Graph error payload. If the payload isn't
+// in the expected format, the raw body becomes the message.
+func newError(statusCode int, header http.Header, data []byte) *Error {
+	ret := &Error{
+		StatusCode:      statusCode,
+		RequestID:       header.Get("request-id"),
+		ClientRequestID: header.Get("client-request-id"),
+	}
+
+	var body errorResponse
+	if err := json.Unmarshal(data, &body); err != nil || body.Error.Code == "" {
+		ret.Message = strings.TrimSpace(string(data))
+		return ret
+	}
+
+	ret.Code = body.Error.Code
+	ret.Message = body.Error.Message
+	ret.Target = body.Error.Target
+	ret.Details = body.Error.Details
+	ret.InnerError = body.Error.InnerError
+
+	if inner := body.Error.InnerError; inner != nil {
+		ret.RequestID = p.Coalesce(inner.RequestID, ret.RequestID)
+		ret.ClientRequestID = p.Coalesce(inner.ClientRequestID, ret.ClientRequestID)
+		ret.Date = inner.Date
+	}
+
+	return ret
+}
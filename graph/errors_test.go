@@ -0,0 +1,73 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestErrReturnsGraphError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{
+			"error": {
+				"code": "Request_ResourceNotFound",
+				"message": "Resource 'group1' does not exist.",
+				"innerError": {
+					"date": "2026-02-03T21:50:00",
+					"request-id": "req-id",
+					"client-request-id": "client-req-id"
+				}
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	client := newClient(server)
+
+	_, err := client.Groups().ById("group1").Get(context.Background())
+	require.Error(t, err)
+	require.True(t, IsNotFound(err))
+	require.False(t, IsThrottled(err))
+
+	var graphErr *Error
+	require.True(t, errors.As(err, &graphErr))
+	require.Equal(t, http.StatusNotFound, graphErr.StatusCode)
+	require.Equal(t, "Request_ResourceNotFound", graphErr.Code)
+	require.Equal(t, "req-id", graphErr.RequestID)
+	require.Equal(t, "client-req-id", graphErr.ClientRequestID)
+	require.Equal(t, "request returned 404 (Request_ResourceNotFound): Resource 'group1' does not exist. [request-id: req-id]", graphErr.Error())
+}
+
+func TestRequestErrThroughRefreshETag(t *testing.T) {
+	server := newErrorServer(http.StatusPreconditionFailed, `{"error":{"code":"PreconditionFailed","message":"etag mismatch"}}`)
+	defer server.Close()
+
+	client := newClient(server)
+	client.eTagCache = make(map[string]string)
+
+	_, err := client.Planner().Tasks().ById("task1").Patch(context.Background(), PatchTaskParams{Title: Set("Updated Task 1")})
+	require.Error(t, err)
+	require.True(t, IsPreconditionFailed(err))
+}
+
+func TestNewErrorUnexpectedBody(t *testing.T) {
+	header := http.Header{}
+	header.Set("request-id", "header-req-id")
+
+	err := newError(http.StatusServiceUnavailable, header, []byte("upstream unavailable\n"))
+	require.Equal(t, "upstream unavailable", err.Message)
+	require.Equal(t, "header-req-id", err.RequestID)
+	require.Empty(t, err.Code)
+}
+
+func newErrorServer(code int, data string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(code)
+		w.Write([]byte(data))
+	}))
+}
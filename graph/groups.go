@@ -2,13 +2,23 @@ package graph
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"net/http"
 )
 
-const groupResource string = "groups"
+const (
+	groupResource        string = "groups"
+	directoryResource    string = "directory"
+	deletedItemsResource string = "deletedItems"
+)
 
 type GroupsRequestBuilder struct {
-	c    *Client
-	path string
+	c        *Client
+	path     string
+	query    *Query
+	maxPages int
 }
 
 func (c *Client) Groups() *GroupsRequestBuilder {
@@ -18,24 +28,151 @@ func (c *Client) Groups() *GroupsRequestBuilder {
 	}
 }
 
-type GetGroupsResponse struct {
-	Value []Group `json:"value"`
+// Query sets the OData query options for the request.
+func (r *GroupsRequestBuilder) Query(q *Query) *GroupsRequestBuilder {
+	r.query = q
+	return r
+}
+
+type GetGroupsResponse = Page[Group]
+
+// MaxPages limits the number of pages fetched by Get. A value <= 0 (the
+// default) fetches every page.
+func (r *GroupsRequestBuilder) MaxPages(n int) *GroupsRequestBuilder {
+	r.maxPages = n
+	return r
 }
 
 func (r *GroupsRequestBuilder) Get(ctx context.Context) ([]Group, error) {
-	var ret GetGroupsResponse
+	return getAll[Group](ctx, r.c, r.query.url(r.path), r.query.header(), r.maxPages)
+}
+
+func (r *GroupsRequestBuilder) Pages() *Pager[Group] {
+	return newPager[Group](r.c, r.query.url(r.path), r.query.header(), r.maxPages)
+}
+
+// All iterates over every Group, see Pager.All.
+func (r *GroupsRequestBuilder) All(ctx context.Context) iter.Seq2[Group, error] {
+	return r.Pages().All(ctx)
+}
+
+// Deleted lists the groups in the directory's deleted items, which can be
+// restored for 30 days.
+func (r *GroupsRequestBuilder) Deleted() *GroupsRequestBuilder {
+	return &GroupsRequestBuilder{
+		c:    r.c,
+		path: joinPath(r.c.BaseURL, directoryResource, deletedItemsResource, "microsoft.graph.group"),
+	}
+}
+
+// Restore restores a deleted group.
+func (r *GroupsRequestBuilder) Restore(ctx context.Context, id string) (Group, error) {
+	var ret Group
+
+	path := joinPath(r.c.BaseURL, directoryResource, deletedItemsResource, id, "restore")
+	resp, err := r.c.send(ctx, http.MethodPost, path, nil, http.StatusOK)
+	if err != nil {
+		return ret, err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(&ret); err != nil {
+		return ret, fmt.Errorf("json.Decode: %v", err)
+	}
+
+	return ret, nil
+}
+
+const unifiedGroupType string = "Unified"
+
+// PostGroupParams creates a group. Use NewUnifiedGroup or NewSecurityGroup
+// to fill in the fields Graph requires for each kind of group. Owners and
+// Members are user IDs.
+type PostGroupParams struct {
+	DisplayName        string   `json:"displayName"`
+	Description        string   `json:"description,omitempty"`
+	GroupTypes         []string `json:"groupTypes"`
+	MailEnabled        bool     `json:"mailEnabled"`
+	MailNickname       string   `json:"mailNickname"`
+	SecurityEnabled    bool     `json:"securityEnabled"`
+	Visibility         string   `json:"visibility,omitempty"`
+	IsAssignableToRole bool     `json:"isAssignableToRole,omitempty"`
+	Owners             []string `json:"-"`
+	Members            []string `json:"-"`
+}
+
+// NewUnifiedGroup returns the params of a Microsoft 365 group, which has a
+// mailbox, a calendar and Planner plans. visibility is "Private" or
+// "Public".
+func NewUnifiedGroup(displayName, mailNickname, visibility string) PostGroupParams {
+	return PostGroupParams{
+		DisplayName:  displayName,
+		GroupTypes:   []string{unifiedGroupType},
+		MailEnabled:  true,
+		MailNickname: mailNickname,
+		Visibility:   visibility,
+	}
+}
+
+// NewSecurityGroup returns the params of a security group, which controls
+// access to resources.
+func NewSecurityGroup(displayName, mailNickname string) PostGroupParams {
+	return PostGroupParams{
+		DisplayName:     displayName,
+		GroupTypes:      []string{},
+		MailNickname:    mailNickname,
+		SecurityEnabled: true,
+	}
+}
+
+type postGroupBody struct {
+	PostGroupParams
+	OwnersBind  []string `json:"owners@odata.bind,omitempty"`
+	MembersBind []string `json:"members@odata.bind,omitempty"`
+}
 
-	if err := get(ctx, r.c, r.path, &ret); err != nil {
-		return nil, err
+func (r *GroupsRequestBuilder) postBody(params PostGroupParams) postGroupBody {
+	body := postGroupBody{PostGroupParams: params}
+	if body.GroupTypes == nil {
+		body.GroupTypes = []string{}
 	}
 
-	return ret.Value, nil
+	for _, id := range params.Owners {
+		body.OwnersBind = append(body.OwnersBind, joinPath(r.c.BaseURL, usersResource, id))
+	}
+	for _, id := range params.Members {
+		body.MembersBind = append(body.MembersBind, joinPath(r.c.BaseURL, usersResource, id))
+	}
+
+	return body
+}
+
+func (r *GroupsRequestBuilder) Post(ctx context.Context, params PostGroupParams) (Group, error) {
+	var ret Group
+
+	resp, err := r.c.post(ctx, r.path, toBody(r.postBody(params)))
+	if err != nil {
+		return ret, err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(&ret); err != nil {
+		return ret, fmt.Errorf("json.Decode: %v", err)
+	}
+
+	return ret, nil
+}
+
+// PostRequest returns the Post request for use in a batch.
+func (r *GroupsRequestBuilder) PostRequest(params PostGroupParams) *BatchRequest {
+	return newBatchRequest(r.c, http.MethodPost, r.path, r.postBody(params))
 }
 
 type GroupItemRequestBuilder struct {
-	Id   string
-	c    *Client
-	path string
+	Id    string
+	c     *Client
+	path  string
+	query *Query
 }
 
 func (r *GroupsRequestBuilder) ById(id string) *GroupItemRequestBuilder {
@@ -46,20 +183,62 @@ func (r *GroupsRequestBuilder) ById(id string) *GroupItemRequestBuilder {
 	}
 }
 
+// Query sets the OData query options for the request.
+func (r *GroupItemRequestBuilder) Query(q *Query) *GroupItemRequestBuilder {
+	r.query = q
+	return r
+}
+
 func (r *GroupItemRequestBuilder) Get(ctx context.Context) (Group, error) {
 	var ret Group
 
-	if err := get(ctx, r.c, r.path, &ret); err != nil {
+	if err := get(ctx, r.c, r.query.url(r.path), r.query.header(), &ret); err != nil {
 		return ret, err
 	}
 
 	return ret, nil
 }
 
+// GetRequest returns the Get request for use in a batch.
+func (r *GroupItemRequestBuilder) GetRequest() *BatchRequest {
+	return newBatchRequest(r.c, http.MethodGet, r.path, nil).withQuery(r.query)
+}
+
+// PatchGroupParams updates a group. Fields left unset aren't changed.
+type PatchGroupParams struct {
+	Classification    Nullable[string] `json:"classification,omitzero"`
+	Description       Nullable[string] `json:"description,omitzero"`
+	DisplayName       Nullable[string] `json:"displayName,omitzero"`
+	MailNickname      Nullable[string] `json:"mailNickname,omitzero"`
+	PreferredLanguage Nullable[string] `json:"preferredLanguage,omitzero"`
+	SecurityEnabled   Nullable[bool]   `json:"securityEnabled,omitzero"`
+	Theme             Nullable[string] `json:"theme,omitzero"`
+	Visibility        Nullable[string] `json:"visibility,omitzero"`
+}
+
+// Patch updates the group. Unlike Planner resources, groups don't use ETags,
+// and Graph doesn't return the updated group.
+func (r *GroupItemRequestBuilder) Patch(ctx context.Context, params PatchGroupParams) error {
+	return r.c.sendNoContent(ctx, http.MethodPatch, r.path, toBody(params))
+}
+
+// PatchRequest returns the Patch request for use in a batch.
+func (r *GroupItemRequestBuilder) PatchRequest(params PatchGroupParams) *BatchRequest {
+	return newBatchRequest(r.c, http.MethodPatch, r.path, params)
+}
+
+// Delete moves the group to the directory's deleted items, see
+// GroupsRequestBuilder.Restore.
+func (r *GroupItemRequestBuilder) Delete(ctx context.Context) error {
+	return r.c.sendNoContent(ctx, http.MethodDelete, r.path, nil)
+}
+
 type ThreadsRequestBuilder struct {
-	GroupId string
-	c       *Client
-	path    string
+	GroupId  string
+	c        *Client
+	path     string
+	query    *Query
+	maxPages int
 }
 
 func (r *GroupItemRequestBuilder) Threads() *ThreadsRequestBuilder {
@@ -70,16 +249,30 @@ func (r *GroupItemRequestBuilder) Threads() *ThreadsRequestBuilder {
 	}
 }
 
-type GetResponse struct {
-	Value []Conversation `json:"value"`
+// Query sets the OData query options for the request.
+func (r *ThreadsRequestBuilder) Query(q *Query) *ThreadsRequestBuilder {
+	r.query = q
+	return r
+}
+
+type GetResponse = Page[Conversation]
+
+// MaxPages limits the number of pages fetched by Get. A value <= 0 (the
+// default) fetches every page.
+func (r *ThreadsRequestBuilder) MaxPages(n int) *ThreadsRequestBuilder {
+	r.maxPages = n
+	return r
 }
 
 func (r *ThreadsRequestBuilder) Get(ctx context.Context) ([]Conversation, error) {
-	var ret GetResponse
+	return getAll[Conversation](ctx, r.c, r.query.url(r.path), r.query.header(), r.maxPages)
+}
 
-	if err := get(ctx, r.c, r.path, &ret); err != nil {
-		return nil, err
-	}
+func (r *ThreadsRequestBuilder) Pages() *Pager[Conversation] {
+	return newPager[Conversation](r.c, r.query.url(r.path), r.query.header(), r.maxPages)
+}
 
-	return ret.Value, nil
+// All iterates over every Conversation, see Pager.All.
+func (r *ThreadsRequestBuilder) All(ctx context.Context) iter.Seq2[Conversation, error] {
+	return r.Pages().All(ctx)
 }
@@ -2,7 +2,9 @@ package graph
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -42,3 +44,132 @@ func TestThreadsGet(t *testing.T) {
 	require.Len(t, threads, 1)
 	require.Equal(t, "thread1", threads[0].ID)
 }
+
+func TestGroupMembersGet(t *testing.T) {
+	server := newTestServer(t, http.MethodGet, "/groups/group1/members", `{"value":[
+		{"@odata.type":"#microsoft.graph.user","id":"user1","displayName":"Alice","userPrincipalName":"alice@contoso.com"},
+		{"@odata.type":"#microsoft.graph.group","id":"group2","displayName":"Nested"},
+		{"@odata.type":"#microsoft.graph.servicePrincipal","id":"sp1","appId":"app1"}
+	]}`)
+	defer server.Close()
+
+	client := newClient(server)
+
+	members, err := client.Groups().ById("group1").Members().Get(context.Background())
+	require.NoError(t, err)
+	require.Len(t, members, 3)
+
+	user, ok := members[0].User()
+	require.True(t, ok)
+	require.Equal(t, "alice@contoso.com", user.UserPrincipalName)
+
+	_, ok = members[0].Group()
+	require.False(t, ok)
+
+	group, ok := members[1].Group()
+	require.True(t, ok)
+	require.Equal(t, "Nested", group.DisplayName)
+
+	sp, ok := members[2].ServicePrincipal()
+	require.True(t, ok)
+	require.Equal(t, "app1", sp.AppID)
+}
+
+func TestGroupMembersAddRemove(t *testing.T) {
+	var requests []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		if r.Method == http.MethodPost {
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.Equal(t, "http://"+r.Host+"/directoryObjects/user1", body["@odata.id"])
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := newClient(server)
+	owners := client.Groups().ById("group1").Owners()
+
+	require.NoError(t, owners.Add(context.Background(), "user1"))
+	require.NoError(t, owners.Remove(context.Background(), "user1"))
+	require.Equal(t, []string{
+		"POST /groups/group1/owners/$ref",
+		"DELETE /groups/group1/owners/user1/$ref",
+	}, requests)
+}
+
+func TestGroupPost(t *testing.T) {
+	var body map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/groups", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"group1","displayName":"Project X","groupTypes":["Unified"]}`))
+	}))
+	defer server.Close()
+
+	client := newClient(server)
+
+	params := NewUnifiedGroup("Project X", "projectx", "Private")
+	params.Owners = []string{"user1"}
+
+	group, err := client.Groups().Post(context.Background(), params)
+	require.NoError(t, err)
+	require.Equal(t, "group1", group.ID)
+
+	require.Equal(t, []any{"Unified"}, body["groupTypes"])
+	require.Equal(t, true, body["mailEnabled"])
+	require.Equal(t, false, body["securityEnabled"])
+	require.Equal(t, "projectx", body["mailNickname"])
+	require.Equal(t, "Private", body["visibility"])
+	require.Equal(t, []any{server.URL + "/users/user1"}, body["owners@odata.bind"])
+	require.NotContains(t, body, "members@odata.bind")
+}
+
+func TestGroupPatchDeleteRestore(t *testing.T) {
+	var requests []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		switch r.Method {
+		case http.MethodPatch:
+			var body map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.Equal(t, map[string]any{"description": nil, "visibility": "Public"}, body)
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodPost:
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id":"group1"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := newClient(server)
+	group := client.Groups().ById("group1")
+
+	require.NoError(t, group.Patch(context.Background(), PatchGroupParams{
+		Description: Null[string](),
+		Visibility:  Set("Public"),
+	}))
+	require.NoError(t, group.Delete(context.Background()))
+
+	restored, err := client.Groups().Restore(context.Background(), "group1")
+	require.NoError(t, err)
+	require.Equal(t, "group1", restored.ID)
+
+	require.Equal(t, []string{
+		"PATCH /groups/group1",
+		"DELETE /groups/group1",
+		"POST /directory/deletedItems/group1/restore",
+	}, requests)
+}
@@ -4,3 +4,13 @@ type ItemBody struct {
 	ContentType string `json:"contentType"`
 	Content     string `json:"content"`
 }
+
+// TextBody returns a plain text ItemBody.
+func TextBody(content string) ItemBody {
+	return ItemBody{ContentType: "text", Content: content}
+}
+
+// HTMLBody returns an HTML ItemBody.
+func HTMLBody(content string) ItemBody {
+	return ItemBody{ContentType: "html", Content: content}
+}
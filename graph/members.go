@@ -0,0 +1,94 @@
+package graph
+
+import (
+	"context"
+	"iter"
+	"net/http"
+)
+
+const (
+	membersResource           string = "members"
+	ownersResource            string = "owners"
+	transitiveMembersResource string = "transitiveMembers"
+	refResource               string = "$ref"
+)
+
+// DirectoryObjectsRequestBuilder lists the members or owners of a group.
+type DirectoryObjectsRequestBuilder struct {
+	GroupId  string
+	c        *Client
+	path     string
+	query    *Query
+	maxPages int
+}
+
+// MembersRequestBuilder lists, adds and removes the direct members or owners
+// of a group.
+type MembersRequestBuilder struct {
+	DirectoryObjectsRequestBuilder
+}
+
+func (r *GroupItemRequestBuilder) Members() *MembersRequestBuilder {
+	return &MembersRequestBuilder{r.directoryObjects(membersResource)}
+}
+
+func (r *GroupItemRequestBuilder) Owners() *MembersRequestBuilder {
+	return &MembersRequestBuilder{r.directoryObjects(ownersResource)}
+}
+
+// TransitiveMembers lists the members of the group, including the members
+// of nested groups.
+func (r *GroupItemRequestBuilder) TransitiveMembers() *DirectoryObjectsRequestBuilder {
+	b := r.directoryObjects(transitiveMembersResource)
+	return &b
+}
+
+func (r *GroupItemRequestBuilder) directoryObjects(resource string) DirectoryObjectsRequestBuilder {
+	return DirectoryObjectsRequestBuilder{
+		GroupId: r.Id,
+		c:       r.c,
+		path:    joinPath(r.path, resource),
+	}
+}
+
+// Query sets the OData query options for the request.
+func (r *DirectoryObjectsRequestBuilder) Query(q *Query) *DirectoryObjectsRequestBuilder {
+	r.query = q
+	return r
+}
+
+// MaxPages limits the number of pages fetched by Get. A value <= 0 (the
+// default) fetches every page.
+func (r *DirectoryObjectsRequestBuilder) MaxPages(n int) *DirectoryObjectsRequestBuilder {
+	r.maxPages = n
+	return r
+}
+
+func (r *DirectoryObjectsRequestBuilder) Get(ctx context.Context) ([]DirectoryObject, error) {
+	return getAll[DirectoryObject](ctx, r.c, r.query.url(r.path), r.query.header(), r.maxPages)
+}
+
+func (r *DirectoryObjectsRequestBuilder) Pages() *Pager[DirectoryObject] {
+	return newPager[DirectoryObject](r.c, r.query.url(r.path), r.query.header(), r.maxPages)
+}
+
+// All iterates over every DirectoryObject, see Pager.All.
+func (r *DirectoryObjectsRequestBuilder) All(ctx context.Context) iter.Seq2[DirectoryObject, error] {
+	return r.Pages().All(ctx)
+}
+
+type refParams struct {
+	OdataID string `json:"@odata.id"`
+}
+
+// Add adds the user, group or service principal with the given ID.
+func (r *MembersRequestBuilder) Add(ctx context.Context, id string) error {
+	params := refParams{OdataID: joinPath(r.c.BaseURL, directoryObjectsResource, id)}
+
+	return r.c.sendNoContent(ctx, http.MethodPost, joinPath(r.path, refResource), toBody(params))
+}
+
+// Remove removes the user, group or service principal with the given ID.
+func (r *MembersRequestBuilder) Remove(ctx context.Context, id string) error {
+	return r.c.sendNoContent(ctx, http.MethodDelete, joinPath(r.path, id, refResource), nil)
+}
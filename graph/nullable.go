@@ -0,0 +1,69 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Nullable is a field of Patch params, which is either left unset (the zero
+// value, omitted from the request), set to a value, or set to null. Unlike
+// omitempty, it can set a field to its zero value, e.g. PercentComplete to 0.
+type Nullable[T any] struct {
+	value T
+	set   bool
+	null  bool
+}
+
+// Set returns a Nullable set to v.
+func Set[T any](v T) Nullable[T] {
+	return Nullable[T]{value: v, set: true}
+}
+
+// Null returns a Nullable which clears the field.
+func Null[T any]() Nullable[T] {
+	return Nullable[T]{set: true, null: true}
+}
+
+// Get returns the value, and whether it was set to something other than
+// null.
+func (n Nullable[T]) Get() (T, bool) {
+	return n.value, n.set && !n.null
+}
+
+// IsSet reports whether the field is sent with the request.
+func (n Nullable[T]) IsSet() bool {
+	return n.set
+}
+
+// IsNull reports whether the field is set to null.
+func (n Nullable[T]) IsNull() bool {
+	return n.null
+}
+
+// IsZero makes the omitzero tag omit unset fields.
+func (n Nullable[T]) IsZero() bool {
+	return !n.set
+}
+
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if !n.set || n.null {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(n.value)
+}
+
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*n = Null[T]()
+		return nil
+	}
+
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*n = Set(v)
+	return nil
+}
@@ -0,0 +1,52 @@
+package graph
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNullableToBody(t *testing.T) {
+	tests := []struct {
+		name   string
+		params any
+		want   string
+	}{
+		{"unset", PatchTaskParams{}, `{}`},
+		{
+			"zero values",
+			PatchTaskParams{PercentComplete: Set(0), Priority: Set(0)},
+			`{"priority":0,"percentComplete":0}`,
+		},
+		{
+			"null",
+			PatchTaskParams{DueDateTime: Null[time.Time](), Title: Set("Reopened")},
+			`{"dueDateTime":null,"title":"Reopened"}`,
+		},
+		{"bucket", PatchBucketParams{OrderHint: Set(" !")}, `{"orderHint":" !"}`},
+		{"plan", PatchPlanParams{Title: Set("")}, `{"title":""}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := io.ReadAll(toBody(tt.params))
+			require.NoError(t, err)
+			require.JSONEq(t, tt.want, string(data))
+		})
+	}
+}
+
+func TestNullableUnmarshal(t *testing.T) {
+	var params PatchTaskParams
+	require.NoError(t, json.Unmarshal([]byte(`{"percentComplete":0,"dueDateTime":null}`), &params))
+
+	percent, ok := params.PercentComplete.Get()
+	require.True(t, ok)
+	require.Equal(t, 0, percent)
+
+	require.True(t, params.DueDateTime.IsNull())
+	require.False(t, params.Title.IsSet())
+}
@@ -0,0 +1,218 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/alamo-ds/msgraph/env"
+	"github.com/s-hammon/p"
+	"golang.org/x/oauth2"
+	"golang.org/x/time/rate"
+)
+
+var (
+	ErrMissingTenantID = errors.New("missing tenant ID")
+	ErrMissingClientID = errors.New("missing client ID")
+)
+
+// Option configures a client created with New.
+type Option func(*options)
+
+type options struct {
+	cfg       *AzureADConfig
+	cred      Credential
+	baseURL   string
+	transport http.RoundTripper
+	timeout   time.Duration
+	rps       int
+	burst     int
+	logger    *slog.Logger
+}
+
+func newOptions(opts ...Option) options {
+	o := options{
+		rps:   DefaultRequestsPerSecondLimit,
+		burst: DefaultBurst,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// WithConfig sets the tenant, app and scopes. By default, New uses the
+// config stored in config.json by `msgraph set`.
+func WithConfig(cfg AzureADConfig) Option {
+	return func(o *options) {
+		o.cfg = &cfg
+	}
+}
+
+// WithCredential sets how the client gets its access tokens. By default,
+// New uses EnvCredential.
+func WithCredential(cred Credential) Option {
+	return func(o *options) {
+		o.cred = cred
+	}
+}
+
+// WithTokenSource sets the source of the client's access tokens, e.g. one
+// shared with another client or library.
+func WithTokenSource(ts oauth2.TokenSource) Option {
+	return WithCredential(CredentialFunc(func(context.Context, AzureADConfig) (oauth2.TokenSource, error) {
+		return ts, nil
+	}))
+}
+
+// WithBaseURL replaces the Graph endpoint of the config's cloud, e.g. to
+// point the client at a proxy. Beta requests go to the sibling /beta path.
+func WithBaseURL(baseURL string) Option {
+	return func(o *options) {
+		o.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithTransport sets the transport used for both Graph and token requests,
+// e.g. to add tracing or a custom proxy.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(o *options) {
+		o.transport = rt
+	}
+}
+
+// WithTimeout limits how long each attempt of a request may wait for the
+// response headers. Reading the body isn't limited, so large downloads
+// aren't cut off; use the request's context for an overall deadline. By
+// default, there is no timeout.
+func WithTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
+// WithRateLimit limits the client to requestsPerSecond, with bursts of up to
+// burst requests. A burst <= 0 is 2x requestsPerSecond.
+func WithRateLimit(requestsPerSecond, burst int) Option {
+	return func(o *options) {
+		o.rps = requestsPerSecond
+		o.burst = burst
+		if burst <= 0 {
+			o.burst = requestsPerSecond * 2
+		}
+	}
+}
+
+// WithLogger sets where the client logs retries and other events worth
+// knowing about. By default, nothing is logged.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// New returns a client configured by opts. It fails if the config or the
+// options are invalid, or if the credential isn't configured.
+func New(ctx context.Context, opts ...Option) (*Client, error) {
+	o := newOptions(opts...)
+
+	cfg, err := o.config()
+	if err != nil {
+		return nil, err
+	}
+	if err := o.validate(cfg); err != nil {
+		return nil, err
+	}
+
+	return o.client(ctx, cfg)
+}
+
+// config returns the config set with WithConfig, or else the one stored in
+// config.json, with its defaults filled in.
+func (o options) config() (AzureADConfig, error) {
+	if o.cfg != nil {
+		return o.cfg.withDefaults(), nil
+	}
+
+	return LoadAzureADConfig()
+}
+
+func (o options) validate(cfg AzureADConfig) error {
+	var errs []error
+
+	if cfg.TenantID == "" {
+		errs = append(errs, ErrMissingTenantID)
+	}
+	if cfg.ClientID == "" {
+		errs = append(errs, ErrMissingClientID)
+	}
+	if _, err := LookupCloud(cfg.Cloud); err != nil {
+		errs = append(errs, err)
+	}
+	if o.baseURL != "" {
+		if u, err := url.Parse(o.baseURL); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, fmt.Errorf("invalid base URL %q", o.baseURL))
+		}
+	}
+	if o.rps <= 0 {
+		errs = append(errs, fmt.Errorf("invalid rate limit %d", o.rps))
+	}
+	if o.timeout < 0 {
+		errs = append(errs, fmt.Errorf("invalid timeout %v", o.timeout))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (o options) client(ctx context.Context, cfg AzureADConfig) (*Client, error) {
+	ctx = o.tokenContext(ctx)
+
+	cred := o.cred
+	if cred == nil {
+		cred = EnvCredential()
+	}
+
+	ts, err := cred.TokenSource(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.build(ctx, cfg, ts), nil
+}
+
+// tokenContext makes token requests go through the same transport as
+// Graph requests.
+func (o options) tokenContext(ctx context.Context) context.Context {
+	if o.transport == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: o.transport, Timeout: o.timeout})
+}
+
+func (o options) build(ctx context.Context, cfg AzureADConfig, ts oauth2.TokenSource) *Client {
+	eTagCache := env.LoadCacheFile().ETags
+	if eTagCache == nil {
+		eTagCache = make(map[string]string)
+		env.WriteCacheFile("eTags", eTagCache)
+	}
+
+	return &Client{
+		BaseURL:    p.Coalesce(o.baseURL, cfg.cloud().BaseURL()),
+		TenantID:   cfg.TenantID,
+		ClientID:   cfg.ClientID,
+		c:          oauth2.NewClient(ctx, ts),
+		limiter:    rate.NewLimiter(rate.Limit(o.rps), o.burst),
+		retry:      DefaultRetryPolicy(),
+		deltaStore: env.DeltaLinkCache{},
+		logger:     o.logger,
+		timeout:    o.timeout,
+		eTagCache:  eTagCache,
+	}
+}
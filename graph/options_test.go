@@ -0,0 +1,163 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func clearConfigEnv(t *testing.T) {
+	t.Helper()
+
+	for _, key := range []string{"TENANT_ID", "CLIENT_ID", "AZURE_TENANT_ID", "AZURE_CLIENT_ID"} {
+		t.Setenv(key, "")
+	}
+}
+
+func TestNewValidation(t *testing.T) {
+	clearConfigEnv(t)
+	ts := WithTokenSource(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token1"}))
+
+	tests := []struct {
+		name string
+		opts []Option
+		want []error
+		msg  string
+	}{
+		{
+			name: "missing tenant and client",
+			opts: []Option{WithConfig(AzureADConfig{}), ts},
+			want: []error{ErrMissingTenantID, ErrMissingClientID},
+		},
+		{
+			name: "missing client",
+			opts: []Option{WithConfig(AzureADConfig{TenantID: "tenant1"}), ts},
+			want: []error{ErrMissingClientID},
+		},
+		{
+			name: "invalid base URL",
+			opts: []Option{WithConfig(AzureADConfig{TenantID: "tenant1", ClientID: "client1"}), ts, WithBaseURL("graph/v1.0")},
+			msg:  "invalid base URL",
+		},
+		{
+			name: "invalid rate limit",
+			opts: []Option{WithConfig(AzureADConfig{TenantID: "tenant1", ClientID: "client1"}), ts, WithRateLimit(0, 0)},
+			msg:  "invalid rate limit",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(context.Background(), tt.opts...)
+			require.Error(t, err)
+			for _, want := range tt.want {
+				require.ErrorIs(t, err, want)
+			}
+			if tt.msg != "" {
+				require.ErrorContains(t, err, tt.msg)
+			}
+		})
+	}
+}
+
+func TestNewOptions(t *testing.T) {
+	clearConfigEnv(t)
+
+	attempts := 0
+	transport := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		require.Equal(t, "https://proxy.example/v1.0/users/user1", req.URL.Scheme+"://"+req.URL.Host+req.URL.Path)
+		require.Equal(t, "Bearer token1", req.Header.Get("Authorization"))
+
+		attempts++
+		if attempts == 1 {
+			return &http.Response{
+				StatusCode: http.StatusServiceUnavailable,
+				Header:     http.Header{"Retry-After": {"0"}},
+				Body:       io.NopCloser(strings.NewReader("")),
+				Request:    req,
+			}, nil
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"id":"user1"}`)),
+			Request:    req,
+		}, nil
+	})
+
+	var logs bytes.Buffer
+	client, err := New(context.Background(),
+		WithConfig(AzureADConfig{TenantID: "tenant1", ClientID: "client1"}),
+		WithTokenSource(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token1"})),
+		WithBaseURL("https://proxy.example/v1.0/"),
+		WithTransport(transport),
+		WithTimeout(time.Minute),
+		WithRateLimit(10, 0),
+		WithLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, "https://proxy.example/v1.0", client.BaseURL)
+	require.Equal(t, time.Minute, client.timeout)
+	require.Equal(t, 20, client.limiter.Burst())
+
+	user, err := client.Users().ById("user1").Get(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "user1", user.ID)
+	require.Equal(t, 2, attempts)
+	require.Contains(t, logs.String(), "retrying request")
+}
+
+func TestTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/slow") {
+			time.Sleep(200 * time.Millisecond)
+		}
+
+		// headers arrive in time, but the body takes longer than the timeout
+		w.WriteHeader(http.StatusOK)
+		for range 3 {
+			w.Write([]byte("chunk"))
+			w.(http.Flusher).Flush()
+			time.Sleep(50 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	client := newClient(server)
+	client.timeout = 100 * time.Millisecond
+
+	_, err := client.get(context.Background(), server.URL+"/slow", nil)
+	require.ErrorContains(t, err, "no response within 100ms")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, err = client.Users().ById("slow").Get(context.Background())
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.Users().ById("user1").Get(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+
+	attachment := client.Groups().ById("group1").Threads().ById("thread1").Posts().ById("post1").Attachments().ById("a1")
+	var buf bytes.Buffer
+	_, err = attachment.Download(context.Background(), &buf)
+	require.NoError(t, err)
+	require.Equal(t, "chunkchunkchunk", buf.String())
+}
@@ -0,0 +1,146 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Planner sorts tasks, buckets, checklist items and assignments by comparing
+// their order hints ordinally. Rather than computing a hint locally, clients
+// send "<previous> <next>!" and Graph replaces it with a hint that sorts
+// between the two, where either side may be empty.
+
+// OrderHintFirst places an item before every other item.
+const OrderHintFirst string = " !"
+
+// OrderHintBetween returns an order hint placing an item between the items
+// with order hints prev and next.
+func OrderHintBetween(prev, next string) string {
+	return prev + " " + next + "!"
+}
+
+// OrderHintBefore returns an order hint placing an item right before the
+// item with order hint next, which must be the first item. Otherwise, use
+// OrderHintBetween with the hint of the item before it.
+func OrderHintBefore(next string) string {
+	return OrderHintBetween("", next)
+}
+
+// OrderHintAfter returns an order hint placing an item right after the item
+// with order hint prev, which must be the last item. Otherwise, use
+// OrderHintBetween with the hint of the item after it.
+func OrderHintAfter(prev string) string {
+	return OrderHintBetween(prev, "")
+}
+
+// SortByOrderHint sorts items the way Planner displays them.
+func SortByOrderHint[T any](items []T, hint func(T) string) {
+	slices.SortStableFunc(items, func(a, b T) int {
+		return strings.Compare(hint(a), hint(b))
+	})
+}
+
+// orderHintNextTo returns the order hint that places the item self right
+// before or after target, among its siblings.
+func orderHintNextTo[T any](siblings []T, id, hint func(T) string, self, target string, before bool) (string, error) {
+	if self == target {
+		return "", fmt.Errorf("can't move %s next to itself", self)
+	}
+
+	siblings = slices.DeleteFunc(slices.Clone(siblings), func(v T) bool {
+		return id(v) == self
+	})
+	SortByOrderHint(siblings, hint)
+
+	i := slices.IndexFunc(siblings, func(v T) bool {
+		return id(v) == target
+	})
+	if i < 0 {
+		return "", fmt.Errorf("%s not found", target)
+	}
+
+	if before {
+		var prev string
+		if i > 0 {
+			prev = hint(siblings[i-1])
+		}
+		return OrderHintBetween(prev, hint(siblings[i])), nil
+	}
+
+	var next string
+	if i+1 < len(siblings) {
+		next = hint(siblings[i+1])
+	}
+	return OrderHintBetween(hint(siblings[i]), next), nil
+}
+
+func taskID(t Task) string            { return t.ID }
+func taskOrderHint(t Task) string     { return t.OrderHint }
+func bucketID(b Bucket) string        { return b.ID }
+func bucketOrderHint(b Bucket) string { return b.OrderHint }
+
+// MoveBefore moves the task right before the task with the given ID, in the
+// plan's list view.
+func (r *TaskRequestBuilder) MoveBefore(ctx context.Context, id string) (Task, error) {
+	return r.moveNextTo(ctx, id, true)
+}
+
+// MoveAfter moves the task right after the task with the given ID, in the
+// plan's list view.
+func (r *TaskRequestBuilder) MoveAfter(ctx context.Context, id string) (Task, error) {
+	return r.moveNextTo(ctx, id, false)
+}
+
+func (r *TaskRequestBuilder) moveNextTo(ctx context.Context, target string, before bool) (Task, error) {
+	var task Task
+	if err := get(ctx, r.c, r.path, nil, &task); err != nil {
+		return task, err
+	}
+
+	tasks, err := r.c.Planner().ById(task.PlanID).Tasks().Get(ctx)
+	if err != nil {
+		return task, err
+	}
+
+	hint, err := orderHintNextTo(tasks, taskID, taskOrderHint, task.ID, target, before)
+	if err != nil {
+		return task, fmt.Errorf("couldn't move task: %w", err)
+	}
+
+	return r.Patch(ctx, PatchTaskParams{OrderHint: Set(hint)})
+}
+
+// MoveBefore moves the bucket right before the bucket with the given ID.
+func (r *BucketItemRequestBuilder) MoveBefore(ctx context.Context, id string) (Bucket, error) {
+	return r.moveNextTo(ctx, id, true)
+}
+
+// MoveAfter moves the bucket right after the bucket with the given ID.
+func (r *BucketItemRequestBuilder) MoveAfter(ctx context.Context, id string) (Bucket, error) {
+	return r.moveNextTo(ctx, id, false)
+}
+
+func (r *BucketItemRequestBuilder) moveNextTo(ctx context.Context, target string, before bool) (Bucket, error) {
+	var bucket Bucket
+	if r.Id == "" {
+		return bucket, fmt.Errorf("id for resource type %T not set", bucket)
+	}
+
+	if err := get(ctx, r.c, r.path, nil, &bucket); err != nil {
+		return bucket, err
+	}
+
+	buckets, err := r.c.Planner().ById(bucket.PlanID).Buckets().Get(ctx)
+	if err != nil {
+		return bucket, err
+	}
+
+	hint, err := orderHintNextTo(buckets, bucketID, bucketOrderHint, bucket.ID, target, before)
+	if err != nil {
+		return bucket, fmt.Errorf("couldn't move bucket: %w", err)
+	}
+
+	return r.Patch(ctx, PatchBucketParams{OrderHint: Set(hint)})
+}
@@ -0,0 +1,69 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderHintNextTo(t *testing.T) {
+	tasks := []Task{
+		{ID: "c", OrderHint: "8586"},
+		{ID: "a", OrderHint: "8584"},
+		{ID: "b", OrderHint: "8585"},
+		{ID: "self", OrderHint: "8583"},
+	}
+
+	tests := []struct {
+		name   string
+		target string
+		before bool
+		want   string
+	}{
+		{"before first", "a", true, " 8584!"},
+		{"before middle", "b", true, "8584 8585!"},
+		{"after middle", "b", false, "8585 8586!"},
+		{"after last", "c", false, "8586 !"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := orderHintNextTo(tasks, taskID, taskOrderHint, "self", tt.target, tt.before)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := orderHintNextTo(tasks, taskID, taskOrderHint, "self", "missing", true)
+	require.Error(t, err)
+}
+
+func TestTaskMoveAfter(t *testing.T) {
+	var patch PatchTaskParams
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /planner/tasks/task1":
+			w.Write([]byte(`{"@odata.etag":"W/\"1\"","id":"task1","planId":"plan1","orderHint":"1"}`))
+		case "GET /planner/plans/plan1/tasks":
+			w.Write([]byte(`{"value":[{"id":"task1","orderHint":"1"},{"id":"task2","orderHint":"2"},{"id":"task3","orderHint":"3"}]}`))
+		case "PATCH /planner/tasks/task1":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&patch))
+			w.Write([]byte(`{"id":"task1","orderHint":"25"}`))
+		default:
+			t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := newClient(server)
+
+	task, err := client.Planner().Tasks().ById("task1").MoveAfter(context.Background(), "task2")
+	require.NoError(t, err)
+	require.Equal(t, Set("2 3!"), patch.OrderHint)
+	require.Equal(t, "25", task.OrderHint)
+}
@@ -1,6 +1,11 @@
 package graph
 
-import "time"
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
 
 type Plan struct {
 	OdataEtag       string        `json:"@odata.etag"`
@@ -13,7 +18,57 @@ type Plan struct {
 }
 
 type PlanContainer struct {
-	ContainerID string `json:"containerId"`
-	Type        string `json:"type"`
-	URL         string `json:"url"`
+	ContainerID string `json:"containerId,omitempty"`
+	Type        string `json:"type,omitempty"`
+	URL         string `json:"url,omitempty"`
+}
+
+// GroupContainer returns the container of a plan owned by a Microsoft 365
+// group.
+func GroupContainer(groupID string) PlanContainer {
+	return PlanContainer{ContainerID: groupID, Type: "group"}
+}
+
+type PlanDetails struct {
+	OdataEtag            string            `json:"@odata.etag"`
+	ID                   string            `json:"id"`
+	SharedWith           map[string]bool   `json:"sharedWith"`
+	CategoryDescriptions map[string]string `json:"categoryDescriptions"`
+}
+
+// CategoryLabel returns the label shown in the UI for category, e.g.
+// "category3". It returns false if the category has no label.
+func (d PlanDetails) CategoryLabel(category string) (string, bool) {
+	label := d.CategoryDescriptions[category]
+	return label, label != ""
+}
+
+// Labels returns the labels of the categories applied to a task, in
+// category order. Categories without a label are returned by name.
+func (d PlanDetails) Labels(appliedCategories map[string]bool) []string {
+	var categories []string
+	for category, applied := range appliedCategories {
+		if applied {
+			categories = append(categories, category)
+		}
+	}
+	slices.SortFunc(categories, func(a, b string) int {
+		return categoryNumber(a) - categoryNumber(b)
+	})
+
+	labels := make([]string, len(categories))
+	for i, category := range categories {
+		labels[i] = category
+		if label, ok := d.CategoryLabel(category); ok {
+			labels[i] = label
+		}
+	}
+
+	return labels
+}
+
+// categoryNumber returns n for "category<n>".
+func categoryNumber(category string) int {
+	n, _ := strconv.Atoi(strings.TrimPrefix(category, "category"))
+	return n
 }
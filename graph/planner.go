@@ -4,7 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"iter"
 	"net/http"
 	"time"
 )
@@ -17,8 +17,11 @@ const (
 )
 
 type PlannerRequestBuilder struct {
-	c    *Client
-	path string
+	c        *Client
+	path     string
+	query    *Query
+	maxPages int
+	groupID  string
 }
 
 func (c *Client) Planner() *PlannerRequestBuilder {
@@ -28,55 +31,137 @@ func (c *Client) Planner() *PlannerRequestBuilder {
 	}
 }
 
+// Query sets the OData query options for the request.
+func (r *PlannerRequestBuilder) Query(q *Query) *PlannerRequestBuilder {
+	r.query = q
+	return r
+}
+
 func (r *GroupItemRequestBuilder) Plans() *PlannerRequestBuilder {
 	return &PlannerRequestBuilder{
-		c:    r.c,
-		path: joinPath(r.path, plannerResource, plansResource),
+		c:       r.c,
+		path:    joinPath(r.path, plannerResource, plansResource),
+		groupID: r.Id,
 	}
 }
 
-type PlanRequestBuilder struct {
+const (
+	favoritePlansResource string = "favoritePlans"
+	recentPlansResource   string = "recentPlans"
+)
+
+// UserPlannerRequestBuilder accesses the Planner resources of a single user.
+type UserPlannerRequestBuilder struct {
 	Id   string
 	c    *Client
 	path string
 }
 
+func (r *UserRequestBuilder) Planner() *UserPlannerRequestBuilder {
+	return &UserPlannerRequestBuilder{
+		Id:   r.Id,
+		c:    r.c,
+		path: joinPath(r.path, plannerResource),
+	}
+}
+
+// Tasks lists the tasks assigned to the user, across every plan.
+func (r *UserPlannerRequestBuilder) Tasks() *TasksRequestBuilder {
+	return &TasksRequestBuilder{
+		Id:   r.Id,
+		c:    r.c,
+		path: joinPath(r.path, tasksResource),
+	}
+}
+
+// Plans lists the plans shared with the user.
+func (r *UserPlannerRequestBuilder) Plans() *PlannerRequestBuilder {
+	return &PlannerRequestBuilder{
+		c:    r.c,
+		path: joinPath(r.path, plansResource),
+	}
+}
+
+// FavoritePlans lists the plans the user marked as favorite. NOTE: this
+// endpoint is only available on the beta API.
+func (r *UserPlannerRequestBuilder) FavoritePlans() *PlannerRequestBuilder {
+	return &PlannerRequestBuilder{
+		c:    r.c,
+		path: joinPath(r.c.betaPath(r.path), favoritePlansResource),
+	}
+}
+
+// RecentPlans lists the plans the user viewed most recently. NOTE: this
+// endpoint is only available on the beta API.
+func (r *UserPlannerRequestBuilder) RecentPlans() *PlannerRequestBuilder {
+	return &PlannerRequestBuilder{
+		c:    r.c,
+		path: joinPath(r.c.betaPath(r.path), recentPlansResource),
+	}
+}
+
+type PlanRequestBuilder struct {
+	Id       string
+	c        *Client
+	path     string
+	query    *Query
+	conflict conflictHandler[Plan, PatchPlanParams]
+}
+
+// ById returns the plan with the given ID. Plans are always addressed from
+// the /planner root, whichever collection the builder lists.
 func (r *PlannerRequestBuilder) ById(id string) *PlanRequestBuilder {
 	return &PlanRequestBuilder{
 		Id:   id,
 		c:    r.c,
-		path: joinPath(r.path, plansResource, id),
+		path: joinPath(r.c.BaseURL, plannerResource, plansResource, id),
 	}
 }
 
-type GetPlansResponse struct {
-	Count int    `json:"@odata.count"`
-	Value []Plan `json:"value"`
+// Query sets the OData query options for the request.
+func (r *PlanRequestBuilder) Query(q *Query) *PlanRequestBuilder {
+	r.query = q
+	return r
 }
 
-func (r *PlannerRequestBuilder) Get(ctx context.Context) ([]Plan, error) {
-	var ret GetPlansResponse
+type GetPlansResponse = Page[Plan]
 
-	if err := get(ctx, r.c, r.path, &ret); err != nil {
-		return nil, err
-	}
+// MaxPages limits the number of pages fetched by Get. A value <= 0 (the
+// default) fetches every page.
+func (r *PlannerRequestBuilder) MaxPages(n int) *PlannerRequestBuilder {
+	r.maxPages = n
+	return r
+}
 
-	return ret.Value, nil
+func (r *PlannerRequestBuilder) Get(ctx context.Context) ([]Plan, error) {
+	return getAll[Plan](ctx, r.c, r.query.url(r.path), r.query.header(), r.maxPages)
+}
+
+func (r *PlannerRequestBuilder) Pages() *Pager[Plan] {
+	return newPager[Plan](r.c, r.query.url(r.path), r.query.header(), r.maxPages)
 }
 
 type TasksRequestBuilder struct {
-	Id   string
-	c    *Client
-	path string
+	Id       string
+	c        *Client
+	path     string
+	query    *Query
+	maxPages int
 }
 
 func (r *PlannerRequestBuilder) Tasks() *TasksRequestBuilder {
 	return &TasksRequestBuilder{
 		c:    r.c,
-		path: joinPath(r.path, tasksResource),
+		path: joinPath(r.c.BaseURL, plannerResource, tasksResource),
 	}
 }
 
+// Query sets the OData query options for the request.
+func (r *TasksRequestBuilder) Query(q *Query) *TasksRequestBuilder {
+	r.query = q
+	return r
+}
+
 func (r *PlanRequestBuilder) Tasks() *TasksRequestBuilder {
 	return &TasksRequestBuilder{
 		Id:   r.Id,
@@ -86,82 +171,132 @@ func (r *PlanRequestBuilder) Tasks() *TasksRequestBuilder {
 }
 
 type TaskRequestBuilder struct {
-	Id   string
-	c    *Client
-	path string
+	Id       string
+	c        *Client
+	path     string
+	query    *Query
+	conflict conflictHandler[Task, PatchTaskParams]
 }
 
+// ById returns the task with the given ID. Like plans, tasks are always
+// addressed from the /planner root, so a task has one ETag cache entry
+// whichever collection it was found in.
 func (r *TasksRequestBuilder) ById(id string) *TaskRequestBuilder {
 	return &TaskRequestBuilder{
 		Id:   id,
 		c:    r.c,
-		path: joinPath(r.path, id),
+		path: joinPath(r.c.BaseURL, plannerResource, tasksResource, id),
 	}
 }
 
+// Query sets the OData query options for the request.
+func (r *TaskRequestBuilder) Query(q *Query) *TaskRequestBuilder {
+	r.query = q
+	return r
+}
+
 func (r *TaskRequestBuilder) Get(ctx context.Context) (Task, error) {
 	var ret Task
-	if err := get(ctx, r.c, r.path, &ret); err != nil {
+	if err := get(ctx, r.c, r.query.url(r.path), r.query.header(), &ret); err != nil {
 		return ret, err
 	}
-	if ret.OdataEtag != "" {
-		r.c.putETag(r.path, ret.OdataEtag)
-	}
 
 	return ret, nil
 }
 
+// GetRequest returns the Get request for use in a batch.
+func (r *TaskRequestBuilder) GetRequest() *BatchRequest {
+	return newBatchRequest(r.c, http.MethodGet, r.path, nil).withQuery(r.query)
+}
+
+// PatchTaskParams updates a task. Fields left unset aren't changed; use
+// Null to clear one. Entries of AppliedCategories and Assignments are merged
+// into the task's, see AssignmentUpdate.
 type PatchTaskParams struct {
-	AppliedCategories    map[string]bool       `json:"appliedCategories,omitempty"`
-	AssigneePriority     string                `json:"assigneePriority,omitempty"`
-	Assignments          map[string]Assignment `json:"assignments,omitempty"`
-	BucketID             string                `json:"bucketId,omitempty"`
-	ConversationThreadID string                `json:"conversationThreadId,omitempty"`
-	DueDateTime          time.Time             `json:"dueDateTime,omitzero"`
-	OrderHint            string                `json:"orderHint,omitempty"`
-	Priority             int                   `json:"priority,omitempty"`
-	PercentComplete      int                   `json:"percentComplete,omitempty"`
-	StartDateTime        time.Time             `json:"startDateTime,omitzero"`
-	Title                string                `json:"title,omitempty"`
+	AppliedCategories    map[string]bool              `json:"appliedCategories,omitempty"`
+	AssigneePriority     Nullable[string]             `json:"assigneePriority,omitzero"`
+	Assignments          map[string]*AssignmentUpdate `json:"assignments,omitempty"`
+	BucketID             Nullable[string]             `json:"bucketId,omitzero"`
+	ConversationThreadID Nullable[string]             `json:"conversationThreadId,omitzero"`
+	DueDateTime          Nullable[time.Time]          `json:"dueDateTime,omitzero"`
+	OrderHint            Nullable[string]             `json:"orderHint,omitzero"`
+	Priority             Nullable[int]                `json:"priority,omitzero"`
+	PercentComplete      Nullable[int]                `json:"percentComplete,omitzero"`
+	StartDateTime        Nullable[time.Time]          `json:"startDateTime,omitzero"`
+	Title                Nullable[string]             `json:"title,omitzero"`
 }
 
 func (r *TaskRequestBuilder) Patch(ctx context.Context, params PatchTaskParams) (Task, error) {
-	var ret Task
+	return patchEntity(ctx, r.c, r.path, params, r.conflict)
+}
 
-	resp, err := r.c.patch(ctx, r.path, toBody(params))
-	if err != nil {
-		return ret, makeReqErr(err)
+// OnConflict sets what Patch does if the task changed since its ETag was
+// cached. The default is ConflictFail.
+func (r *TaskRequestBuilder) OnConflict(policy ConflictPolicy) *TaskRequestBuilder {
+	r.conflict.policy = policy
+	return r
+}
+
+// MergeOnConflict resolves conflicts with fn, see ConflictMerge.
+func (r *TaskRequestBuilder) MergeOnConflict(fn MergeFunc[Task, PatchTaskParams]) *TaskRequestBuilder {
+	r.conflict.policy = ConflictMerge
+	r.conflict.merge = fn
+	return r
+}
+
+// PatchRequest returns the Patch request for use in a batch. The If-Match
+// header is filled from the ETag cache when the batch is sent.
+func (r *TaskRequestBuilder) PatchRequest(params PatchTaskParams) *BatchRequest {
+	return newBatchPatchRequest(r.c, r.path, params)
+}
+
+// Assign assigns the task to the given users, on top of its current
+// assignees.
+func (r *TaskRequestBuilder) Assign(ctx context.Context, userIDs ...string) (Task, error) {
+	params := PatchTaskParams{Assignments: make(map[string]*AssignmentUpdate, len(userIDs))}
+	for _, id := range userIDs {
+		params.Assignments[id] = NewAssignment()
 	}
 
-	if err := handlePatchPostResp(resp, &ret); err != nil {
-		return ret, err
+	return r.Patch(ctx, params)
+}
+
+// Unassign removes the given users from the task's assignees.
+func (r *TaskRequestBuilder) Unassign(ctx context.Context, userIDs ...string) (Task, error) {
+	params := PatchTaskParams{Assignments: make(map[string]*AssignmentUpdate, len(userIDs))}
+	for _, id := range userIDs {
+		params.Assignments[id] = nil
 	}
 
-	return ret, nil
+	return r.Patch(ctx, params)
+}
+
+func (r *TaskRequestBuilder) Delete(ctx context.Context) error {
+	return r.c.delete(ctx, r.path)
 }
 
 type PostTaskParams struct {
-	PlanID                   string                `json:"planId"`
-	BucketID                 string                `json:"bucketId,omitempty"`
-	Title                    string                `json:"title"`
-	OrderHint                string                `json:"orderHint,omitempty"`
-	AssigneePriority         string                `json:"assigneePriority,omitempty"`
-	PercentComplete          int                   `json:"percentComplete,omitempty"`
-	StartDateTime            time.Time             `json:"startDateTime,omitzero"`
-	CreatedDateTime          time.Time             `json:"createdDateTime,omitzero"`
-	DueDateTime              time.Time             `json:"dueDateTime,omitzero"`
-	HasDescription           bool                  `json:"hasDescription,omitempty"`
-	PreviewType              string                `json:"previewType,omitempty"`
-	CompletedDateTime        time.Time             `json:"completedDateTime,omitzero"`
-	ReferenceCount           int                   `json:"referenceCount,omitempty"`
-	ChecklistItemCount       int                   `json:"checklistItemCount,omitempty"`
-	ActiveChecklistItemCount int                   `json:"activeChecklistItemCount,omitempty"`
-	ConversationThreadID     string                `json:"conversationThreadId,omitempty"`
-	Priority                 int                   `json:"priority,omitempty"`
-	CreatedBy                IdentitySet           `json:"createdBy,omitzero"`
-	CompletedBy              IdentitySet           `json:"completedBy,omitzero"`
-	AppliedCategories        map[string]bool       `json:"appliedCategories,omitempty"`
-	Assignments              map[string]Assignment `json:"assignments,omitempty"`
+	PlanID                   string                       `json:"planId"`
+	BucketID                 string                       `json:"bucketId,omitempty"`
+	Title                    string                       `json:"title"`
+	OrderHint                string                       `json:"orderHint,omitempty"`
+	AssigneePriority         string                       `json:"assigneePriority,omitempty"`
+	PercentComplete          int                          `json:"percentComplete,omitempty"`
+	StartDateTime            time.Time                    `json:"startDateTime,omitzero"`
+	CreatedDateTime          time.Time                    `json:"createdDateTime,omitzero"`
+	DueDateTime              time.Time                    `json:"dueDateTime,omitzero"`
+	HasDescription           bool                         `json:"hasDescription,omitempty"`
+	PreviewType              string                       `json:"previewType,omitempty"`
+	CompletedDateTime        time.Time                    `json:"completedDateTime,omitzero"`
+	ReferenceCount           int                          `json:"referenceCount,omitempty"`
+	ChecklistItemCount       int                          `json:"checklistItemCount,omitempty"`
+	ActiveChecklistItemCount int                          `json:"activeChecklistItemCount,omitempty"`
+	ConversationThreadID     string                       `json:"conversationThreadId,omitempty"`
+	Priority                 int                          `json:"priority,omitempty"`
+	CreatedBy                IdentitySet                  `json:"createdBy,omitzero"`
+	CompletedBy              IdentitySet                  `json:"completedBy,omitzero"`
+	AppliedCategories        map[string]bool              `json:"appliedCategories,omitempty"`
+	Assignments              map[string]*AssignmentUpdate `json:"assignments,omitempty"`
 }
 
 func (r *TasksRequestBuilder) Post(ctx context.Context, params PostTaskParams) (Task, error) {
@@ -172,25 +307,47 @@ func (r *TasksRequestBuilder) Post(ctx context.Context, params PostTaskParams) (
 		return ret, err
 	}
 
-	if err := handlePatchPostResp(resp, &ret); err != nil {
+	if err := handlePatchPostResp(r.c, r.path, resp, &ret); err != nil {
 		return ret, err
 	}
 
 	return ret, nil
 }
 
+// PostRequest returns the Post request for use in a batch.
+func (r *TasksRequestBuilder) PostRequest(params PostTaskParams) *BatchRequest {
+	return newBatchRequest(r.c, http.MethodPost, r.path, params)
+}
+
+// handlePatchPostResp decodes the response into val and caches the returned
+// ETag. For a PATCH, path is the resource's own path; for a POST, it is the
+// collection's path, and the new resource's ID is appended to it.
 // NOTE: val must be a pointer to a map or struct
-func handlePatchPostResp[T any](resp *http.Response, val T) error {
+func handlePatchPostResp[T any](c *Client, path string, resp *http.Response, val T) error {
 	defer resp.Body.Close()
 
 	switch resp.StatusCode {
 	default:
 		return requestErr(resp)
 	case http.StatusNoContent:
-		log.Printf("request successful but returned no content (%d)\n", resp.StatusCode)
+		c.log().Debug("request successful but returned no content", "url", resp.Request.URL.String(), "status", resp.StatusCode)
 		return nil
 	case http.StatusOK, http.StatusCreated:
-		if err := json.NewDecoder(resp.Body).Decode(val); err != nil {
+		var data json.RawMessage
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			return fmt.Errorf("json.Decode: %v", err)
+		}
+
+		if resp.StatusCode == http.StatusCreated {
+			var created struct {
+				ID string `json:"id"`
+			}
+			json.Unmarshal(data, &created)
+			path = joinPath(path, created.ID)
+		}
+		c.putETagFromBody(path, data)
+
+		if err := json.Unmarshal(data, val); err != nil {
 			return fmt.Errorf("json.Decode: %v", err)
 		}
 
@@ -198,26 +355,34 @@ func handlePatchPostResp[T any](resp *http.Response, val T) error {
 	}
 }
 
-type GetTasksResponse struct {
-	Count int    `json:"@odata.count"`
-	Value []Task `json:"value"`
+type GetTasksResponse = Page[Task]
+
+// MaxPages limits the number of pages fetched by Get. A value <= 0 (the
+// default) fetches every page.
+func (r *TasksRequestBuilder) MaxPages(n int) *TasksRequestBuilder {
+	r.maxPages = n
+	return r
 }
 
 func (r *TasksRequestBuilder) Get(ctx context.Context) ([]Task, error) {
-	var ret GetTasksResponse
-
-	if err := get(ctx, r.c, r.path, &ret); err != nil {
-		return nil, err
-	}
+	return getAll[Task](ctx, r.c, r.query.url(r.path), r.query.header(), r.maxPages)
+}
 
-	return ret.Value, nil
+func (r *TasksRequestBuilder) Pages() *Pager[Task] {
+	return newPager[Task](r.c, r.query.url(r.path), r.query.header(), r.maxPages)
+}
 
+// All iterates over every Task, see Pager.All.
+func (r *TasksRequestBuilder) All(ctx context.Context) iter.Seq2[Task, error] {
+	return r.Pages().All(ctx)
 }
 
 type TaskItemRequestBuilder struct {
-	Id   string
-	c    *Client
-	path string
+	Id       string
+	c        *Client
+	path     string
+	query    *Query
+	conflict conflictHandler[TaskDetails, PatchTaskDetailsParams]
 }
 
 func (r *TaskRequestBuilder) Details() *TaskItemRequestBuilder {
@@ -228,49 +393,244 @@ func (r *TaskRequestBuilder) Details() *TaskItemRequestBuilder {
 	}
 }
 
+// Query sets the OData query options for the request.
+func (r *TaskItemRequestBuilder) Query(q *Query) *TaskItemRequestBuilder {
+	r.query = q
+	return r
+}
+
 func (r *TaskItemRequestBuilder) Get(ctx context.Context) (TaskDetails, error) {
 	var ret TaskDetails
 
-	if err := get(ctx, r.c, r.path, &ret); err != nil {
+	if err := get(ctx, r.c, r.query.url(r.path), r.query.header(), &ret); err != nil {
 		return ret, err
 	}
 
 	return ret, nil
 }
 
-type PatchPlanParams struct {
-	Title string `json:"title,omitempty"`
+// GetRequest returns the Get request for use in a batch.
+func (r *TaskItemRequestBuilder) GetRequest() *BatchRequest {
+	return newBatchRequest(r.c, http.MethodGet, r.path, nil).withQuery(r.query)
 }
 
-func (r *PlanRequestBuilder) Get(ctx context.Context) (Plan, error) {
+// PatchTaskDetailsParams updates a task's details. Checklist items and
+// references are merged into the existing ones by key, so only the entries
+// present are changed; a nil entry removes it. Use the helper methods rather
+// than filling in the maps by hand.
+type PatchTaskDetailsParams struct {
+	Description Nullable[string]                    `json:"description,omitzero"`
+	PreviewType Nullable[string]                    `json:"previewType,omitzero"`
+	Checklist   map[string]*ChecklistItemUpdate     `json:"checklist,omitempty"`
+	References  map[string]*ExternalReferenceUpdate `json:"references,omitempty"`
+}
+
+func (r *TaskItemRequestBuilder) Patch(ctx context.Context, params PatchTaskDetailsParams) (TaskDetails, error) {
+	return patchEntity(ctx, r.c, r.path, params, r.conflict)
+}
+
+// OnConflict sets what Patch does if the details changed since their ETag
+// was cached. The default is ConflictFail.
+func (r *TaskItemRequestBuilder) OnConflict(policy ConflictPolicy) *TaskItemRequestBuilder {
+	r.conflict.policy = policy
+	return r
+}
+
+// MergeOnConflict resolves conflicts with fn, see ConflictMerge.
+func (r *TaskItemRequestBuilder) MergeOnConflict(fn MergeFunc[TaskDetails, PatchTaskDetailsParams]) *TaskItemRequestBuilder {
+	r.conflict.policy = ConflictMerge
+	r.conflict.merge = fn
+	return r
+}
+
+// PatchRequest returns the Patch request for use in a batch. The If-Match
+// header is filled from the ETag cache when the batch is sent.
+func (r *TaskItemRequestBuilder) PatchRequest(params PatchTaskDetailsParams) *BatchRequest {
+	return newBatchPatchRequest(r.c, r.path, params)
+}
+
+type PostPlanParams struct {
+	Container PlanContainer `json:"container"`
+	Title     string        `json:"title"`
+}
+
+// Post creates a plan. If the request builder was returned by a group's
+// Plans and no container is set, the plan is created in that group.
+func (r *PlannerRequestBuilder) Post(ctx context.Context, params PostPlanParams) (Plan, error) {
 	var ret Plan
 
-	if err := get(ctx, r.c, r.path, &ret); err != nil {
+	if params.Container == (PlanContainer{}) && r.groupID != "" {
+		params.Container = GroupContainer(r.groupID)
+	}
+
+	path := joinPath(r.c.BaseURL, plannerResource, plansResource)
+	resp, err := r.c.post(ctx, path, toBody(params))
+	if err != nil {
+		return ret, err
+	}
+
+	if err := handlePatchPostResp(r.c, path, resp, &ret); err != nil {
 		return ret, err
 	}
 
 	return ret, nil
 }
 
-func (r *PlanRequestBuilder) Patch(ctx context.Context, params PatchPlanParams) (Plan, error) {
-	var ret Plan
+// PostRequest returns the Post request for use in a batch.
+func (r *PlannerRequestBuilder) PostRequest(params PostPlanParams) *BatchRequest {
+	if params.Container == (PlanContainer{}) && r.groupID != "" {
+		params.Container = GroupContainer(r.groupID)
+	}
 
-	resp, err := r.c.patch(ctx, r.path, toBody(params))
-	if err != nil {
-		return ret, makeReqErr(err)
+	return newBatchRequest(r.c, http.MethodPost, joinPath(r.c.BaseURL, plannerResource, plansResource), params)
+}
+
+type PlanDetailsRequestBuilder struct {
+	Id       string
+	c        *Client
+	path     string
+	query    *Query
+	conflict conflictHandler[PlanDetails, PatchPlanDetailsParams]
+}
+
+func (r *PlanRequestBuilder) Details() *PlanDetailsRequestBuilder {
+	return &PlanDetailsRequestBuilder{
+		Id:   r.Id,
+		c:    r.c,
+		path: joinPath(r.path, "details"),
 	}
+}
+
+// Query sets the OData query options for the request.
+func (r *PlanDetailsRequestBuilder) Query(q *Query) *PlanDetailsRequestBuilder {
+	r.query = q
+	return r
+}
 
-	if err := handlePatchPostResp(resp, &ret); err != nil {
+func (r *PlanDetailsRequestBuilder) Get(ctx context.Context) (PlanDetails, error) {
+	var ret PlanDetails
+
+	if err := get(ctx, r.c, r.query.url(r.path), r.query.header(), &ret); err != nil {
 		return ret, err
 	}
 
 	return ret, nil
 }
 
+// GetRequest returns the Get request for use in a batch.
+func (r *PlanDetailsRequestBuilder) GetRequest() *BatchRequest {
+	return newBatchRequest(r.c, http.MethodGet, r.path, nil).withQuery(r.query)
+}
+
+// PatchPlanDetailsParams updates a plan's details. Only the categories and
+// users present are changed, see the helper methods.
+type PatchPlanDetailsParams struct {
+	CategoryDescriptions map[string]*string `json:"categoryDescriptions,omitempty"`
+	SharedWith           map[string]bool    `json:"sharedWith,omitempty"`
+}
+
+// SetCategoryLabel sets the label of category, e.g. "category3".
+func (p *PatchPlanDetailsParams) SetCategoryLabel(category, label string) {
+	if p.CategoryDescriptions == nil {
+		p.CategoryDescriptions = make(map[string]*string)
+	}
+	p.CategoryDescriptions[category] = &label
+}
+
+// ClearCategoryLabel removes the label of category.
+func (p *PatchPlanDetailsParams) ClearCategoryLabel(category string) {
+	if p.CategoryDescriptions == nil {
+		p.CategoryDescriptions = make(map[string]*string)
+	}
+	p.CategoryDescriptions[category] = nil
+}
+
+// Share shares the plan with the user, if set, or stops sharing it.
+func (p *PatchPlanDetailsParams) Share(userID string, shared bool) {
+	if p.SharedWith == nil {
+		p.SharedWith = make(map[string]bool)
+	}
+	p.SharedWith[userID] = shared
+}
+
+func (r *PlanDetailsRequestBuilder) Patch(ctx context.Context, params PatchPlanDetailsParams) (PlanDetails, error) {
+	return patchEntity(ctx, r.c, r.path, params, r.conflict)
+}
+
+// OnConflict sets what Patch does if the details changed since their ETag
+// was cached. The default is ConflictFail.
+func (r *PlanDetailsRequestBuilder) OnConflict(policy ConflictPolicy) *PlanDetailsRequestBuilder {
+	r.conflict.policy = policy
+	return r
+}
+
+// MergeOnConflict resolves conflicts with fn, see ConflictMerge.
+func (r *PlanDetailsRequestBuilder) MergeOnConflict(fn MergeFunc[PlanDetails, PatchPlanDetailsParams]) *PlanDetailsRequestBuilder {
+	r.conflict.policy = ConflictMerge
+	r.conflict.merge = fn
+	return r
+}
+
+// PatchRequest returns the Patch request for use in a batch. The If-Match
+// header is filled from the ETag cache when the batch is sent.
+func (r *PlanDetailsRequestBuilder) PatchRequest(params PatchPlanDetailsParams) *BatchRequest {
+	return newBatchPatchRequest(r.c, r.path, params)
+}
+
+// PatchPlanParams updates a plan. Fields left unset aren't changed.
+type PatchPlanParams struct {
+	Title Nullable[string] `json:"title,omitzero"`
+}
+
+func (r *PlanRequestBuilder) Get(ctx context.Context) (Plan, error) {
+	var ret Plan
+
+	if err := get(ctx, r.c, r.query.url(r.path), r.query.header(), &ret); err != nil {
+		return ret, err
+	}
+
+	return ret, nil
+}
+
+// GetRequest returns the Get request for use in a batch.
+func (r *PlanRequestBuilder) GetRequest() *BatchRequest {
+	return newBatchRequest(r.c, http.MethodGet, r.path, nil).withQuery(r.query)
+}
+
+func (r *PlanRequestBuilder) Patch(ctx context.Context, params PatchPlanParams) (Plan, error) {
+	return patchEntity(ctx, r.c, r.path, params, r.conflict)
+}
+
+// OnConflict sets what Patch does if the plan changed since its ETag was
+// cached. The default is ConflictFail.
+func (r *PlanRequestBuilder) OnConflict(policy ConflictPolicy) *PlanRequestBuilder {
+	r.conflict.policy = policy
+	return r
+}
+
+// MergeOnConflict resolves conflicts with fn, see ConflictMerge.
+func (r *PlanRequestBuilder) MergeOnConflict(fn MergeFunc[Plan, PatchPlanParams]) *PlanRequestBuilder {
+	r.conflict.policy = ConflictMerge
+	r.conflict.merge = fn
+	return r
+}
+
+// PatchRequest returns the Patch request for use in a batch. The If-Match
+// header is filled from the ETag cache when the batch is sent.
+func (r *PlanRequestBuilder) PatchRequest(params PatchPlanParams) *BatchRequest {
+	return newBatchPatchRequest(r.c, r.path, params)
+}
+
+func (r *PlanRequestBuilder) Delete(ctx context.Context) error {
+	return r.c.delete(ctx, r.path)
+}
+
 type BucketsRequestBuilder struct {
-	Id   string
-	c    *Client
-	path string
+	Id       string
+	c        *Client
+	path     string
+	query    *Query
+	maxPages int
 }
 
 func (r *PlanRequestBuilder) Buckets() *BucketsRequestBuilder {
@@ -281,43 +641,65 @@ func (r *PlanRequestBuilder) Buckets() *BucketsRequestBuilder {
 	}
 }
 
-type GetBucketsResponse struct {
-	Count int      `json:"@odata.count"`
-	Value []Bucket `json:"value"`
+// Query sets the OData query options for the request.
+func (r *BucketsRequestBuilder) Query(q *Query) *BucketsRequestBuilder {
+	r.query = q
+	return r
+}
+
+type GetBucketsResponse = Page[Bucket]
+
+// MaxPages limits the number of pages fetched by Get. A value <= 0 (the
+// default) fetches every page.
+func (r *BucketsRequestBuilder) MaxPages(n int) *BucketsRequestBuilder {
+	r.maxPages = n
+	return r
 }
 
 func (r *BucketsRequestBuilder) Get(ctx context.Context) ([]Bucket, error) {
-	var ret GetBucketsResponse
+	return getAll[Bucket](ctx, r.c, r.query.url(r.path), r.query.header(), r.maxPages)
+}
 
-	if err := get(ctx, r.c, r.path, &ret); err != nil {
-		return nil, err
-	}
+func (r *BucketsRequestBuilder) Pages() *Pager[Bucket] {
+	return newPager[Bucket](r.c, r.query.url(r.path), r.query.header(), r.maxPages)
+}
 
-	return ret.Value, nil
+// All iterates over every Bucket, see Pager.All.
+func (r *BucketsRequestBuilder) All(ctx context.Context) iter.Seq2[Bucket, error] {
+	return r.Pages().All(ctx)
 }
 
 type BucketItemRequestBuilder struct {
-	Id   string
-	c    *Client
-	path string
+	Id       string
+	c        *Client
+	path     string
+	query    *Query
+	conflict conflictHandler[Bucket, PatchBucketParams]
 }
 
 func (r *PlannerRequestBuilder) Buckets() *BucketItemRequestBuilder {
 	return &BucketItemRequestBuilder{
 		c:    r.c,
-		path: joinPath(r.path, bucketsResource),
+		path: joinPath(r.c.BaseURL, plannerResource, bucketsResource),
 	}
 }
 
+// Query sets the OData query options for the request.
+func (r *BucketItemRequestBuilder) Query(q *Query) *BucketItemRequestBuilder {
+	r.query = q
+	return r
+}
+
 func (r *BucketItemRequestBuilder) ById(id string) *BucketItemRequestBuilder {
 	r.Id = id
 	r.path = joinPath(r.path, id)
 	return r
 }
 
+// PatchBucketParams updates a bucket. Fields left unset aren't changed.
 type PatchBucketParams struct {
-	Name      string `json:"name,omitempty"`
-	OrderHint string `json:"orderHint,omitempty"`
+	Name      Nullable[string] `json:"name,omitzero"`
+	OrderHint Nullable[string] `json:"orderHint,omitzero"`
 }
 
 func (r *BucketItemRequestBuilder) Patch(ctx context.Context, params PatchBucketParams) (Bucket, error) {
@@ -329,16 +711,35 @@ func (r *BucketItemRequestBuilder) Patch(ctx context.Context, params PatchBucket
 		return ret, fmt.Errorf("id for resource type %T not set", ret)
 	}
 
-	resp, err := r.c.patch(ctx, r.path, toBody(params))
-	if err != nil {
-		return ret, makeReqErr(err)
-	}
+	return patchEntity(ctx, r.c, r.path, params, r.conflict)
+}
 
-	if err := handlePatchPostResp(resp, &ret); err != nil {
-		return ret, err
+// OnConflict sets what Patch does if the bucket changed since its ETag was
+// cached. The default is ConflictFail.
+func (r *BucketItemRequestBuilder) OnConflict(policy ConflictPolicy) *BucketItemRequestBuilder {
+	r.conflict.policy = policy
+	return r
+}
+
+// MergeOnConflict resolves conflicts with fn, see ConflictMerge.
+func (r *BucketItemRequestBuilder) MergeOnConflict(fn MergeFunc[Bucket, PatchBucketParams]) *BucketItemRequestBuilder {
+	r.conflict.policy = ConflictMerge
+	r.conflict.merge = fn
+	return r
+}
+
+// PatchRequest returns the Patch request for use in a batch. The If-Match
+// header is filled from the ETag cache when the batch is sent.
+func (r *BucketItemRequestBuilder) PatchRequest(params PatchBucketParams) *BatchRequest {
+	return newBatchPatchRequest(r.c, r.path, params)
+}
+
+func (r *BucketItemRequestBuilder) Delete(ctx context.Context) error {
+	if r.Id == "" {
+		return fmt.Errorf("id for resource type %T not set", Bucket{})
 	}
 
-	return ret, nil
+	return r.c.delete(ctx, r.path)
 }
 
 type PostBucketParams struct {
@@ -355,9 +756,14 @@ func (r *BucketItemRequestBuilder) Post(ctx context.Context, params PostBucketPa
 		return ret, err
 	}
 
-	if err := handlePatchPostResp(resp, &ret); err != nil {
+	if err := handlePatchPostResp(r.c, r.path, resp, &ret); err != nil {
 		return ret, err
 	}
 
 	return ret, nil
 }
+
+// PostRequest returns the Post request for use in a batch.
+func (r *BucketItemRequestBuilder) PostRequest(params PostBucketParams) *BatchRequest {
+	return newBatchRequest(r.c, http.MethodPost, r.path, params)
+}
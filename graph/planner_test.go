@@ -2,6 +2,7 @@ package graph
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -42,11 +43,80 @@ func TestPlanPatch(t *testing.T) {
 		server.URL + "/planner/plans/plan1": "W/\"test-etag\"",
 	}
 
-	plan, err := client.Planner().ById("plan1").Patch(context.Background(), PatchPlanParams{Title: "Updated Plan 1"})
+	plan, err := client.Planner().ById("plan1").Patch(context.Background(), PatchPlanParams{Title: Set("Updated Plan 1")})
 	require.NoError(t, err)
 	require.Equal(t, "Updated Plan 1", plan.Title)
 }
 
+func TestPlanPost(t *testing.T) {
+	var body PostPlanParams
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/planner/plans", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"@odata.etag":"W/\"1\"","id":"plan1","title":"Plan 1"}`))
+	}))
+	defer server.Close()
+
+	client := newClient(server)
+
+	plan, err := client.Groups().ById("group1").Plans().Post(context.Background(), PostPlanParams{Title: "Plan 1"})
+	require.NoError(t, err)
+	require.Equal(t, "plan1", plan.ID)
+	require.Equal(t, GroupContainer("group1"), body.Container)
+	eTag, ok := client.getETag(server.URL + "/planner/plans/plan1")
+	require.True(t, ok)
+	require.Equal(t, `W/"1"`, eTag)
+}
+
+func TestPlanDetailsPatch(t *testing.T) {
+	var body map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPatch, r.Method)
+		require.Equal(t, "/planner/plans/plan1/details", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id":"plan1","categoryDescriptions":{"category1":"Urgent"}}`))
+	}))
+	defer server.Close()
+
+	client := newClient(server)
+	client.eTagCache = map[string]string{
+		server.URL + "/planner/plans/plan1/details": `W/"test-etag"`,
+	}
+
+	var params PatchPlanDetailsParams
+	params.SetCategoryLabel("category1", "Urgent")
+	params.ClearCategoryLabel("category3")
+	params.Share("user1", true)
+
+	details, err := client.Planner().ById("plan1").Details().Patch(context.Background(), params)
+	require.NoError(t, err)
+	require.Equal(t, "Urgent", details.CategoryDescriptions["category1"])
+	require.Equal(t, map[string]any{"category1": "Urgent", "category3": nil}, body["categoryDescriptions"])
+	require.Equal(t, map[string]any{"user1": true}, body["sharedWith"])
+}
+
+func TestPlanDetailsLabels(t *testing.T) {
+	details := PlanDetails{CategoryDescriptions: map[string]string{
+		"category2":  "Blocked",
+		"category10": "Backend",
+	}}
+
+	labels := details.Labels(map[string]bool{
+		"category10": true,
+		"category2":  true,
+		"category3":  true,
+		"category4":  false,
+	})
+	require.Equal(t, []string{"Blocked", "category3", "Backend"}, labels)
+}
+
 func TestTasksGet(t *testing.T) {
 	server := newTestServer(t, http.MethodGet, "/planner/plans/plan1/tasks", `{"value":[{"id":"task1","title":"Task 1"}]}`)
 	defer server.Close()
@@ -79,11 +149,46 @@ func TestTaskPatch(t *testing.T) {
 		server.URL + "/planner/tasks/task1": "W/\"test-etag\"",
 	}
 
-	task, err := client.Planner().Tasks().ById("task1").Patch(context.Background(), PatchTaskParams{Title: "Updated Task 1"})
+	task, err := client.Planner().Tasks().ById("task1").Patch(context.Background(), PatchTaskParams{Title: Set("Updated Task 1")})
 	require.NoError(t, err)
 	require.Equal(t, "Updated Task 1", task.Title)
 }
 
+func TestTaskAssign(t *testing.T) {
+	var bodies []map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPatch, r.Method)
+		require.Equal(t, "/planner/tasks/task1", r.URL.Path)
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		bodies = append(bodies, body)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"@odata.etag":"W/\"2\"","id":"task1"}`))
+	}))
+	defer server.Close()
+
+	client := newClient(server)
+	client.eTagCache = map[string]string{
+		server.URL + "/planner/tasks/task1": `W/"1"`,
+	}
+
+	task := client.Planner().Tasks().ById("task1")
+
+	_, err := task.Assign(context.Background(), "user1", "user2")
+	require.NoError(t, err)
+	_, err = task.Unassign(context.Background(), "user1")
+	require.NoError(t, err)
+
+	assigned := map[string]any{"@odata.type": "#microsoft.graph.plannerAssignment", "orderHint": " !"}
+	require.Equal(t, []map[string]any{
+		{"assignments": map[string]any{"user1": assigned, "user2": assigned}},
+		{"assignments": map[string]any{"user1": nil}},
+	}, bodies)
+}
+
 func TestTaskPost(t *testing.T) {
 	server := newTestServer(t, http.MethodPost, "/planner/tasks", `{"id":"task1","title":"New Task 1"}`)
 	defer server.Close()
@@ -95,6 +200,84 @@ func TestTaskPost(t *testing.T) {
 	require.Equal(t, "New Task 1", task.Title)
 }
 
+func TestTaskDelete(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/planner/tasks/task1", r.URL.Path)
+
+		switch r.Method {
+		case http.MethodGet:
+			// Mock refreshETag
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"@odata.etag":"W/\"test-etag\""}`))
+		case http.MethodDelete:
+			require.Equal(t, "W/\"test-etag\"", r.Header.Get("If-Match"))
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	client := newClient(server)
+	client.eTagCache = make(map[string]string)
+
+	err := client.Planner().Tasks().ById("task1").Delete(context.Background())
+	require.NoError(t, err)
+
+	_, ok := client.getETag(server.URL + "/planner/tasks/task1")
+	require.False(t, ok)
+}
+
+func TestBucketDelete(t *testing.T) {
+	server := newTestServer(t, http.MethodDelete, "/planner/buckets/bucket1", "")
+	defer server.Close()
+
+	client := newClient(server)
+	client.eTagCache = map[string]string{
+		server.URL + "/planner/buckets/bucket1": "W/\"test-etag\"",
+	}
+
+	err := client.Planner().Buckets().ById("bucket1").Delete(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, client.eTagCache)
+
+	err = client.Planner().Buckets().Delete(context.Background())
+	require.Error(t, err)
+}
+
+func TestPlanDelete(t *testing.T) {
+	server := newTestServer(t, http.MethodDelete, "/planner/plans/plan1", "")
+	defer server.Close()
+
+	client := newClient(server)
+	client.eTagCache = map[string]string{
+		server.URL + "/planner/plans/plan1": "W/\"test-etag\"",
+	}
+
+	err := client.Planner().ById("plan1").Delete(context.Background())
+	require.NoError(t, err)
+}
+
+func TestPlanDeleteError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodDelete, r.Method)
+
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":{"code":"NotFound","message":"plan not found"}}`))
+	}))
+	defer server.Close()
+
+	client := newClient(server)
+	client.eTagCache = map[string]string{
+		server.URL + "/planner/plans/plan1": "W/\"test-etag\"",
+	}
+
+	err := client.Planner().ById("plan1").Delete(context.Background())
+	require.NotContains(t, err.Error(), "couldn't create request")
+
+	var graphErr *Error
+	require.ErrorAs(t, err, &graphErr)
+	require.Equal(t, http.StatusNotFound, graphErr.StatusCode)
+}
+
 func TestBucketsGet(t *testing.T) {
 	server := newTestServer(t, http.MethodGet, "/planner/plans/plan1/buckets", `{"value":[{"id":"bucket1","name":"Bucket 1"}]}`)
 	defer server.Close()
@@ -116,7 +299,7 @@ func TestBucketPatch(t *testing.T) {
 		server.URL + "/planner/buckets/bucket1": "W/\"test-etag\"",
 	}
 
-	bucket, err := client.Planner().Buckets().ById("bucket1").Patch(context.Background(), PatchBucketParams{Name: "Updated Bucket 1"})
+	bucket, err := client.Planner().Buckets().ById("bucket1").Patch(context.Background(), PatchBucketParams{Name: Set("Updated Bucket 1")})
 	require.NoError(t, err)
 	require.Equal(t, "Updated Bucket 1", bucket.Name)
 }
@@ -144,6 +327,84 @@ func TestTaskDetailsGet(t *testing.T) {
 	require.Equal(t, "Task 1 Description", details.Description)
 }
 
+func TestTaskDetailsPatch(t *testing.T) {
+	var body map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPatch, r.Method)
+		require.Equal(t, "/planner/tasks/task1/details", r.URL.Path)
+		require.Equal(t, `W/"test-etag"`, r.Header.Get("If-Match"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id":"task1","description":"Updated"}`))
+	}))
+	defer server.Close()
+
+	client := newClient(server)
+	client.eTagCache = map[string]string{
+		server.URL + "/planner/tasks/task1/details": `W/"test-etag"`,
+	}
+
+	params := PatchTaskDetailsParams{Description: Set("Updated")}
+	id := params.AddChecklistItem("Write docs")
+	params.CheckChecklistItem("item1")
+	params.ReorderChecklistItem("item1", " !")
+	params.RemoveChecklistItem("item2")
+	params.AddReference("https://contoso.com/spec.docx", "Spec", "Word")
+	params.RemoveReference("http://old.example")
+
+	details, err := client.Planner().Tasks().ById("task1").Details().Patch(context.Background(), params)
+	require.NoError(t, err)
+	require.Equal(t, "Updated", details.Description)
+
+	require.Equal(t, "Updated", body["description"])
+	require.Equal(t, map[string]any{
+		id:      map[string]any{"@odata.type": "#microsoft.graph.plannerChecklistItem", "title": "Write docs"},
+		"item1": map[string]any{"@odata.type": "#microsoft.graph.plannerChecklistItem", "isChecked": true, "orderHint": " !"},
+		"item2": nil,
+	}, body["checklist"])
+	require.Equal(t, map[string]any{
+		"https%3A//contoso%2Ecom/spec%2Edocx": map[string]any{"@odata.type": "#microsoft.graph.plannerExternalReference", "alias": "Spec", "type": "Word"},
+		"http%3A//old%2Eexample":              nil,
+	}, body["references"])
+}
+
+func TestTaskDetailsPatchClearDescription(t *testing.T) {
+	var body map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id":"task1"}`))
+	}))
+	defer server.Close()
+
+	client := newClient(server)
+	client.eTagCache = map[string]string{
+		server.URL + "/planner/tasks/task1/details": `W/"test-etag"`,
+	}
+
+	details, err := client.Planner().Tasks().ById("task1").Details().Patch(context.Background(), PatchTaskDetailsParams{Description: Null[string]()})
+	require.NoError(t, err)
+	require.Empty(t, details.Description)
+	require.Equal(t, map[string]any{"description": nil}, body)
+}
+
+func TestReferenceKey(t *testing.T) {
+	raw := "https://contoso.com/a%20b#c@d"
+	key := EncodeReferenceKey(raw)
+	require.Equal(t, "https%3A//contoso%2Ecom/a%2520b%23c%40d", key)
+	require.Equal(t, raw, DecodeReferenceKey(key))
+}
+
+func TestNewGUID(t *testing.T) {
+	id := newGUID()
+	require.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, id)
+	require.NotEqual(t, id, newGUID())
+}
+
 func TestPlannerGet(t *testing.T) {
 	server := newTestServer(t, http.MethodGet, "/planner", `{"value":[{"id":"plan1","title":"Plan 1"}]}`)
 	defer server.Close()
@@ -156,6 +417,60 @@ func TestPlannerGet(t *testing.T) {
 	require.Equal(t, "plan1", plans[0].ID)
 }
 
+func TestUserPlannerTasksGet(t *testing.T) {
+	server := newTestServer(t, http.MethodGet, "/users/alice@contoso.com/planner/tasks", `{"value":[{"id":"task1","planId":"plan1"}]}`)
+	defer server.Close()
+
+	client := newClient(server)
+
+	tasks, err := client.Users().ById("alice@contoso.com").Planner().Tasks().Get(context.Background())
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	require.Equal(t, "plan1", tasks[0].PlanID)
+}
+
+func TestMePlannerPlansGet(t *testing.T) {
+	server := newTestServer(t, http.MethodGet, "/me/planner/plans", `{"value":[{"id":"plan1"}]}`)
+	defer server.Close()
+
+	client := newClient(server)
+
+	plans, err := client.Me().Planner().Plans().Get(context.Background())
+	require.NoError(t, err)
+	require.Len(t, plans, 1)
+}
+
+func TestFavoritePlansGet(t *testing.T) {
+	server := newTestServer(t, http.MethodGet, "/beta/me/planner/favoritePlans", `{"value":[{"id":"plan1"}]}`)
+	defer server.Close()
+
+	client := newClient(server)
+	client.BaseURL = server.URL + "/v1.0"
+
+	plans, err := client.Me().Planner().FavoritePlans().Get(context.Background())
+	require.NoError(t, err)
+	require.Len(t, plans, 1)
+}
+
+func TestUserPlansById(t *testing.T) {
+	server := newTestServer(t, http.MethodGet, "/planner/plans/plan1", `{"id":"plan1"}`)
+	defer server.Close()
+
+	client := newClient(server)
+
+	plans := client.Me().Planner().Plans()
+	require.Equal(t, server.URL+"/planner/plans/plan1", plans.ById("plan1").path)
+	require.Equal(t, server.URL+"/planner/tasks", plans.Tasks().path)
+	require.Equal(t, server.URL+"/planner/buckets", plans.Buckets().path)
+	require.Equal(t, server.URL+"/planner/plans/plan1", client.Groups().ById("group1").Plans().ById("plan1").path)
+	require.Equal(t, server.URL+"/planner/tasks/task1", client.Me().Planner().Tasks().ById("task1").path)
+	require.Equal(t, server.URL+"/planner/tasks/task1", client.Planner().ById("plan1").Tasks().ById("task1").path)
+
+	plan, err := client.Me().Planner().RecentPlans().ById("plan1").Get(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "plan1", plan.ID)
+}
+
 func newTestServer(t *testing.T, code, path, data string) *httptest.Server {
 	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		require.Equal(t, code, r.Method)
@@ -166,6 +481,8 @@ func newTestServer(t *testing.T, code, path, data string) *httptest.Server {
 			w.WriteHeader(http.StatusOK)
 		case http.MethodPost:
 			w.WriteHeader(http.StatusCreated)
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
 		}
 		w.Write([]byte(data))
 	}))
@@ -0,0 +1,303 @@
+package graph
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/s-hammon/p"
+)
+
+// Query holds the OData query options for a request, e.g.
+//
+//	q := graph.NewQuery().
+//	    Select("id", "displayName").
+//	    Filter(graph.StartsWith("displayName", "Project")).
+//	    Top(50)
+//
+//	groups, err := client.Groups().Query(q).Get(ctx)
+//
+// A nil *Query is valid and adds no options.
+type Query struct {
+	selects  []string
+	expand   []string
+	orderBy  []string
+	filter   Expr
+	search   string
+	top      int
+	skip     int
+	count    bool
+	eventual bool
+}
+
+func NewQuery() *Query {
+	return &Query{}
+}
+
+func (q *Query) Select(fields ...string) *Query {
+	q.selects = append(q.selects, fields...)
+	return q
+}
+
+func (q *Query) Expand(fields ...string) *Query {
+	q.expand = append(q.expand, fields...)
+	return q
+}
+
+// OrderBy sorts by the given fields, each optionally followed by " asc" or
+// " desc".
+func (q *Query) OrderBy(fields ...string) *Query {
+	q.orderBy = append(q.orderBy, fields...)
+	return q
+}
+
+func (q *Query) Filter(expr Expr) *Query {
+	q.filter = expr
+	return q
+}
+
+// Search sets $search, e.g. `"displayName:project"`. Graph expects the
+// search terms to be wrapped in double quotes, which are added if missing.
+func (q *Query) Search(terms string) *Query {
+	if !strings.HasPrefix(terms, `"`) {
+		terms = strconv.Quote(terms)
+	}
+
+	q.search = terms
+	return q
+}
+
+func (q *Query) Top(n int) *Query {
+	q.top = n
+	return q
+}
+
+func (q *Query) Skip(n int) *Query {
+	q.skip = n
+	return q
+}
+
+func (q *Query) Count() *Query {
+	q.count = true
+	return q
+}
+
+// Eventual sends the "ConsistencyLevel: eventual" header even if none of the
+// options require it.
+func (q *Query) Eventual() *Query {
+	q.eventual = true
+	return q
+}
+
+// advanced reports whether the query uses Graph's advanced query
+// capabilities, which require the "ConsistencyLevel: eventual" header.
+func (q *Query) advanced() bool {
+	return q.eventual || q.count || q.search != "" || q.filter.advanced
+}
+
+// Encode returns the query string, without the leading "?".
+func (q *Query) Encode() string {
+	if q == nil {
+		return ""
+	}
+
+	var params []string
+	add := func(key, val string) {
+		// Graph expects spaces as %20 rather than +
+		params = append(params, key+"="+strings.ReplaceAll(url.QueryEscape(val), "+", "%20"))
+	}
+
+	if len(q.selects) > 0 {
+		add("$select", strings.Join(q.selects, ","))
+	}
+	if len(q.expand) > 0 {
+		add("$expand", strings.Join(q.expand, ","))
+	}
+	if q.filter.s != "" {
+		add("$filter", q.filter.s)
+	}
+	if len(q.orderBy) > 0 {
+		add("$orderby", strings.Join(q.orderBy, ","))
+	}
+	if q.search != "" {
+		add("$search", q.search)
+	}
+	if q.top > 0 {
+		add("$top", strconv.Itoa(q.top))
+	}
+	if q.skip > 0 {
+		add("$skip", strconv.Itoa(q.skip))
+	}
+	if q.count {
+		add("$count", "true")
+	}
+
+	return strings.Join(params, "&")
+}
+
+// clone returns a copy of q that can be changed without changing q. A nil q
+// clones to an empty query.
+func (q *Query) clone() *Query {
+	ret := NewQuery()
+	if q == nil {
+		return ret
+	}
+
+	*ret = *q
+	ret.selects = slices.Clone(q.selects)
+	ret.expand = slices.Clone(q.expand)
+	ret.orderBy = slices.Clone(q.orderBy)
+	return ret
+}
+
+func (q *Query) url(path string) string {
+	if params := q.Encode(); params != "" {
+		return path + "?" + params
+	}
+
+	return path
+}
+
+func (q *Query) header() http.Header {
+	if q == nil || !q.advanced() {
+		return nil
+	}
+
+	header := make(http.Header)
+	header.Set("ConsistencyLevel", "eventual")
+	return header
+}
+
+// Expr is an OData $filter expression. Build these with the helpers below,
+// or with Raw for anything they don't cover.
+type Expr struct {
+	s        string
+	advanced bool
+}
+
+func (e Expr) String() string {
+	return e.s
+}
+
+// Raw wraps a hand-written filter expression.
+func Raw(s string) Expr {
+	return Expr{s: s}
+}
+
+func Eq(field string, val any) Expr {
+	return compare(field, "eq", val)
+}
+
+// Ne is an advanced query, see https://learn.microsoft.com/en-us/graph/aad-advanced-queries
+func Ne(field string, val any) Expr {
+	e := compare(field, "ne", val)
+	e.advanced = true
+	return e
+}
+
+func Gt(field string, val any) Expr {
+	return compare(field, "gt", val)
+}
+
+func Ge(field string, val any) Expr {
+	return compare(field, "ge", val)
+}
+
+func Lt(field string, val any) Expr {
+	return compare(field, "lt", val)
+}
+
+func Le(field string, val any) Expr {
+	return compare(field, "le", val)
+}
+
+func StartsWith(field, prefix string) Expr {
+	return Expr{s: p.Format("startswith(%s,%s)", field, literal(prefix))}
+}
+
+// EndsWith is an advanced query, see https://learn.microsoft.com/en-us/graph/aad-advanced-queries
+func EndsWith(field, suffix string) Expr {
+	return Expr{s: p.Format("endswith(%s,%s)", field, literal(suffix)), advanced: true}
+}
+
+// Any builds a lambda expression over a collection, e.g.
+//
+//	graph.Any("groupTypes", func(x string) graph.Expr {
+//	    return graph.Eq(x, "Unified")
+//	})
+//
+// yields "groupTypes/any(x:x eq 'Unified')".
+func Any(collection string, cond func(x string) Expr) Expr {
+	return lambda(collection, "any", cond)
+}
+
+func All(collection string, cond func(x string) Expr) Expr {
+	return lambda(collection, "all", cond)
+}
+
+func And(exprs ...Expr) Expr {
+	return join("and", exprs)
+}
+
+func Or(exprs ...Expr) Expr {
+	return join("or", exprs)
+}
+
+// Not is an advanced query, see https://learn.microsoft.com/en-us/graph/aad-advanced-queries
+func Not(expr Expr) Expr {
+	return Expr{s: p.Format("not(%s)", expr.s), advanced: true}
+}
+
+func compare(field, op string, val any) Expr {
+	return Expr{s: p.Format("%s %s %s", field, op, literal(val))}
+}
+
+func lambda(collection, op string, cond func(x string) Expr) Expr {
+	e := cond("x")
+	return Expr{s: p.Format("%s/%s(x:%s)", collection, op, e.s), advanced: e.advanced}
+}
+
+func join(op string, exprs []Expr) Expr {
+	var (
+		ret   Expr
+		parts []string
+	)
+
+	for _, e := range exprs {
+		if e.s == "" {
+			continue
+		}
+
+		parts = append(parts, e.s)
+		ret.advanced = ret.advanced || e.advanced
+	}
+
+	switch len(parts) {
+	case 0:
+	case 1:
+		ret.s = parts[0]
+	default:
+		ret.s = "(" + strings.Join(parts, " "+op+" ") + ")"
+	}
+
+	return ret
+}
+
+// literal formats val as an OData literal. Strings are single-quoted, with
+// any single quotes escaped by doubling them.
+func literal(val any) string {
+	switch v := val.(type) {
+	default:
+		return fmt.Sprint(v)
+	case nil:
+		return "null"
+	case string:
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	}
+}
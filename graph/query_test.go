@@ -0,0 +1,134 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryEncode(t *testing.T) {
+	tests := []struct {
+		name  string
+		query *Query
+		want  string
+	}{
+		{"nil", nil, ""},
+		{"empty", NewQuery(), ""},
+		{
+			"select and top",
+			NewQuery().Select("id", "displayName").Top(10),
+			"$select=id%2CdisplayName&$top=10",
+		},
+		{
+			"filter with spaces and quotes",
+			NewQuery().Filter(Eq("displayName", "O'Brien's team")),
+			"$filter=displayName%20eq%20%27O%27%27Brien%27%27s%20team%27",
+		},
+		{
+			"everything",
+			NewQuery().Expand("members").OrderBy("displayName desc").Search("displayName:proj").Skip(5).Count(),
+			"$expand=members&$orderby=displayName%20desc&$search=%22displayName%3Aproj%22&$skip=5&$count=true",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.query.Encode())
+		})
+	}
+}
+
+func TestFilterExpressions(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     Expr
+		want     string
+		advanced bool
+	}{
+		{"eq string", Eq("mail", "a@contoso.com"), "mail eq 'a@contoso.com'", false},
+		{"eq bool", Eq("accountEnabled", true), "accountEnabled eq true", false},
+		{"ne", Ne("department", nil), "department ne null", true},
+		{"startswith", StartsWith("displayName", "Proj"), "startswith(displayName,'Proj')", false},
+		{
+			"any lambda",
+			Any("groupTypes", func(x string) Expr { return Eq(x, "Unified") }),
+			"groupTypes/any(x:x eq 'Unified')",
+			false,
+		},
+		{
+			"and/or",
+			And(Eq("a", 1), Or(Eq("b", 2), EndsWith("mail", "@contoso.com"))),
+			"(a eq 1 and (b eq 2 or endswith(mail,'@contoso.com')))",
+			true,
+		},
+		{"single and", And(Eq("a", 1)), "a eq 1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.expr.String())
+			require.Equal(t, tt.advanced, NewQuery().Filter(tt.expr).header() != nil)
+		})
+	}
+}
+
+func TestUsersGetWithQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/users", r.URL.Path)
+		require.Equal(t, "id,displayName,mail,userPrincipalName,accountEnabled,createdDateTime,deletedDateTime,employeeLeaveDateTime,department", r.URL.Query().Get("$select"))
+		require.Equal(t, "true", r.URL.Query().Get("$count"))
+		require.Equal(t, "eventual", r.Header.Get("ConsistencyLevel"))
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"@odata.count":1,"value":[{"id":"user1","department":"Ops"}]}`))
+	}))
+	defer server.Close()
+
+	client := newClient(server)
+
+	users, err := client.Users().Query(NewQuery().Count()).Select("department", "id").Get(context.Background())
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	require.Equal(t, "Ops", users[0].Department)
+}
+
+func TestUsersSelectDoesNotChangeQuery(t *testing.T) {
+	server := newTestServer(t, http.MethodGet, "/users/user1", `{"id":"user1"}`)
+	defer server.Close()
+
+	client := newClient(server)
+
+	q := NewQuery().Select("id").Count()
+	users := client.Users().Query(q).Select("department")
+
+	require.Equal(t, "$select=id&$count=true", q.Encode())
+	require.Equal(t, "$select=id%2Cdepartment&$count=true", users.query.Encode())
+
+	q = NewQuery().Select("id")
+	_, err := client.Users().ById("user1").Query(q).Select("department").Get(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "$select=id", q.Encode())
+}
+
+func TestUsersByIdDropsCollectionQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/users/user1", r.URL.Path)
+		require.Empty(t, r.URL.Query().Get("$filter"))
+		require.Empty(t, r.URL.Query().Get("$count"))
+		require.Empty(t, r.Header.Get("ConsistencyLevel"))
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id":"user1"}`))
+	}))
+	defer server.Close()
+
+	client := newClient(server)
+
+	q := NewQuery().Filter(Eq("department", "Ops")).Count()
+	user, err := client.Users().Query(q).ById("user1").Get(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "user1", user.ID)
+}
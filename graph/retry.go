@@ -0,0 +1,231 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultMaxAttempts = 4
+	DefaultBaseDelay   = 500 * time.Millisecond
+	DefaultMaxDelay    = 30 * time.Second
+	DefaultJitter      = 0.2
+)
+
+// RetryPolicy controls how Client retries requests that Graph rejects with a
+// transient status code, e.g. when throttling.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts per request, including the
+	// first one. A value <= 1 disables retries.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry. It doubles on every
+	// subsequent attempt, up to MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// Jitter randomizes each backoff delay by up to +/- the given fraction,
+	// e.g. 0.2 for +/- 20%.
+	Jitter float64
+	// RetryableStatusCodes are the response codes which trigger a retry.
+	RetryableStatusCodes []int
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: DefaultMaxAttempts,
+		BaseDelay:   DefaultBaseDelay,
+		MaxDelay:    DefaultMaxDelay,
+		Jitter:      DefaultJitter,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+func (p RetryPolicy) retryable(code int) bool {
+	return slices.Contains(p.RetryableStatusCodes, code)
+}
+
+// backoff returns the delay before the given retry attempt (starting at 1).
+// Retry-After, when sent by Graph, takes precedence over the computed delay.
+func (p RetryPolicy) backoff(attempt int, header http.Header) time.Duration {
+	if d, ok := parseRetryAfter(header.Get("Retry-After"), time.Now()); ok {
+		return d
+	}
+
+	d := float64(p.BaseDelay) * math.Pow(2, float64(attempt-1))
+	if p.MaxDelay > 0 {
+		d = math.Min(d, float64(p.MaxDelay))
+	}
+	if p.Jitter > 0 {
+		// #nosec G404 -- jitter doesn't need a secure random source
+		d += d * p.Jitter * (rand.Float64()*2 - 1)
+	}
+
+	return time.Duration(d)
+}
+
+// parseRetryAfter handles both forms of the Retry-After header: a number of
+// seconds, or an HTTP date.
+func parseRetryAfter(val string, now time.Time) (time.Duration, bool) {
+	if val == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(val); err == nil {
+		return time.Duration(max(secs, 0)) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(val); err == nil {
+		return max(t.Sub(now), 0), true
+	}
+
+	return 0, false
+}
+
+// WithRetryPolicy replaces the client's retry policy.
+func (c *Client) WithRetryPolicy(policy RetryPolicy) *Client {
+	c.retry = policy
+	return c
+}
+
+// throttle halves the limiter's rate after Graph responds with 429, and
+// restores the original rate once the throttling window has passed. Repeated
+// 429s lower the rate further and extend the window.
+func (c *Client) throttle(d time.Duration) {
+	c.throttleMu.Lock()
+	defer c.throttleMu.Unlock()
+
+	if c.throttleTimer == nil {
+		c.restoreLimit = c.limiter.Limit()
+	} else {
+		c.throttleTimer.Stop()
+	}
+
+	c.limiter.SetLimit(max(c.limiter.Limit()/2, 1))
+	c.throttleTimer = time.AfterFunc(2*d, func() {
+		c.throttleMu.Lock()
+		defer c.throttleMu.Unlock()
+
+		c.limiter.SetLimit(c.restoreLimit)
+		c.throttleTimer = nil
+	})
+}
+
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	attempts := max(c.retry.MaxAttempts, 1)
+
+	for attempt := 1; ; attempt++ {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("limiter.Wait: %w", err)
+		}
+
+		resp, err := c.attempt(req)
+		if err != nil {
+			return nil, err
+		}
+
+		// Requests whose body can't be rewound are never replayed.
+		replayable := req.Body == nil || req.GetBody != nil
+		if attempt >= attempts || !replayable || !c.retry.retryable(resp.StatusCode) {
+			return resp, nil
+		}
+
+		delay := c.retry.backoff(attempt, resp.Header)
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode == http.StatusTooManyRequests {
+			c.throttle(delay)
+		}
+
+		c.log().Info("retrying request",
+			"method", req.Method, "url", req.URL.String(), "status", resp.StatusCode,
+			"attempt", attempt, "delay", delay)
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+
+		if req, err = rewind(req); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// attempt sends req once. The client's timeout only covers waiting for the
+// response headers, so streaming a large body isn't cut off.
+func (c *Client) attempt(req *http.Request) (*http.Response, error) {
+	if c.timeout <= 0 {
+		// #nosec G704 -- path is internally constructed
+		return c.c.Do(req)
+	}
+
+	ctx, cancel := context.WithCancel(req.Context())
+	timer := time.AfterFunc(c.timeout, cancel)
+
+	// #nosec G704 -- path is internally constructed
+	resp, err := c.c.Do(req.WithContext(ctx))
+	if !timer.Stop() {
+		if err == nil {
+			resp.Body.Close()
+		}
+		cancel()
+		return nil, fmt.Errorf("no response within %v: %w", c.timeout, context.DeadlineExceeded)
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	resp.Body = cancelOnClose{resp.Body, cancel}
+	return resp, nil
+}
+
+// cancelOnClose releases a request's context once its body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b cancelOnClose) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// rewind returns a copy of req with a fresh body, so it can be sent again.
+func rewind(req *http.Request) (*http.Request, error) {
+	next := req.Clone(req.Context())
+	if req.GetBody == nil {
+		return next, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("couldn't replay request body: %w", err)
+	}
+	next.Body = body
+
+	return next, nil
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
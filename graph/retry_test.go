@@ -0,0 +1,107 @@
+package graph
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func TestClientRetriesThrottledRequests(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+
+		data, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"planId":"plan1","title":"New Task 1"}`, string(data))
+
+		switch attempts {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":"task1","title":"New Task 1"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := newClient(server).WithRetryPolicy(RetryPolicy{
+		MaxAttempts:          3,
+		BaseDelay:            time.Millisecond,
+		RetryableStatusCodes: []int{http.StatusTooManyRequests, http.StatusServiceUnavailable},
+	})
+
+	task, err := client.Planner().Tasks().Post(context.Background(), PostTaskParams{Title: "New Task 1", PlanID: "plan1"})
+	require.NoError(t, err)
+	require.Equal(t, "task1", task.ID)
+	require.Equal(t, 3, attempts)
+}
+
+func TestClientGivesUpAfterMaxAttempts(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusGatewayTimeout)
+	}))
+	defer server.Close()
+
+	client := newClient(server).WithRetryPolicy(RetryPolicy{
+		MaxAttempts:          2,
+		BaseDelay:            time.Millisecond,
+		RetryableStatusCodes: []int{http.StatusGatewayTimeout},
+	})
+
+	_, err := client.Groups().Get(context.Background())
+	require.Error(t, err)
+	require.Equal(t, 2, attempts)
+}
+
+func TestClientThrottleLowersRate(t *testing.T) {
+	client := &Client{limiter: rate.NewLimiter(rate.Limit(100), 200)}
+
+	client.throttle(time.Hour)
+	require.Equal(t, rate.Limit(50), client.limiter.Limit())
+
+	client.throttle(10 * time.Millisecond)
+	require.Equal(t, rate.Limit(25), client.limiter.Limit())
+
+	require.Eventually(t, func() bool {
+		client.throttleMu.Lock()
+		defer client.throttleMu.Unlock()
+		return client.limiter.Limit() == rate.Limit(100)
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 2, 3, 21, 50, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		val  string
+		want time.Duration
+		ok   bool
+	}{
+		{"empty", "", 0, false},
+		{"seconds", "7", 7 * time.Second, true},
+		{"http date", now.Add(30 * time.Second).Format(http.TimeFormat), 30 * time.Second, true},
+		{"date in the past", now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"garbage", "soon", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.val, now)
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -1,6 +1,11 @@
 package graph
 
-import "time"
+import (
+	"crypto/rand"
+	"fmt"
+	"strings"
+	"time"
+)
 
 type Task struct {
 	OdataEtag                string                `json:"@odata.etag"`
@@ -34,6 +39,21 @@ type Assignment struct {
 	AssignedDateTime time.Time   `json:"assignedDateTime"`
 }
 
+const assignmentType string = "#microsoft.graph.plannerAssignment"
+
+// AssignmentUpdate is the value of an assignee in PatchTaskParams and
+// PostTaskParams. A nil AssignmentUpdate unassigns the user.
+type AssignmentUpdate struct {
+	OdataType string `json:"@odata.type"`
+	OrderHint string `json:"orderHint"`
+}
+
+// NewAssignment returns an assignment which places the task first in the
+// assignee's list.
+func NewAssignment() *AssignmentUpdate {
+	return &AssignmentUpdate{OdataType: assignmentType, OrderHint: OrderHintFirst}
+}
+
 type TaskDetails struct {
 	OdataEtag   string                       `json:"@odata.etag"`
 	ID          string                       `json:"id"`
@@ -48,13 +68,152 @@ type ChecklistItem struct {
 	IsChecked            bool        `json:"isChecked"`
 	OrderHint            string      `json:"orderHint"`
 	LastModifiedBy       IdentitySet `json:"lastModifiedBy"`
-	LastModifiedDateTime time.Time   `json:"lastModifiedByDateTime"`
+	LastModifiedDateTime time.Time   `json:"lastModifiedDateTime"`
 }
 
 type ExternalReference struct {
 	Alias                string      `json:"alias"`
 	LastModifiedBy       IdentitySet `json:"lastModifiedBy"`
-	LastModifiedDateTime time.Time   `json:"lastModifiedByDateTime"`
+	LastModifiedDateTime time.Time   `json:"lastModifiedDateTime"`
 	PreviewPriority      string      `json:"previewPriority"`
 	Type                 string      `json:"type"`
 }
+
+const (
+	checklistItemType     string = "#microsoft.graph.plannerChecklistItem"
+	externalReferenceType string = "#microsoft.graph.plannerExternalReference"
+)
+
+// ChecklistItemUpdate is a change to a single checklist item. Unset fields
+// are left as they are.
+type ChecklistItemUpdate struct {
+	OdataType string `json:"@odata.type"`
+	Title     string `json:"title,omitempty"`
+	IsChecked *bool  `json:"isChecked,omitempty"`
+	OrderHint string `json:"orderHint,omitempty"`
+}
+
+// ExternalReferenceUpdate is a change to a single reference. Unset fields
+// are left as they are.
+type ExternalReferenceUpdate struct {
+	OdataType       string `json:"@odata.type"`
+	Alias           string `json:"alias,omitempty"`
+	PreviewPriority string `json:"previewPriority,omitempty"`
+	Type            string `json:"type,omitempty"`
+}
+
+// AddChecklistItem adds a new, unchecked item to the checklist and returns
+// the ID generated for it.
+func (p *PatchTaskDetailsParams) AddChecklistItem(title string) string {
+	id := newGUID()
+	p.checklistItem(id).Title = title
+	return id
+}
+
+func (p *PatchTaskDetailsParams) CheckChecklistItem(id string) {
+	checked := true
+	p.checklistItem(id).IsChecked = &checked
+}
+
+func (p *PatchTaskDetailsParams) UncheckChecklistItem(id string) {
+	checked := false
+	p.checklistItem(id).IsChecked = &checked
+}
+
+// RenameChecklistItem changes the title of an existing checklist item.
+func (p *PatchTaskDetailsParams) RenameChecklistItem(id, title string) {
+	p.checklistItem(id).Title = title
+}
+
+// ReorderChecklistItem moves a checklist item to the position given by
+// orderHint.
+func (p *PatchTaskDetailsParams) ReorderChecklistItem(id, orderHint string) {
+	p.checklistItem(id).OrderHint = orderHint
+}
+
+func (p *PatchTaskDetailsParams) RemoveChecklistItem(id string) {
+	if p.Checklist == nil {
+		p.Checklist = make(map[string]*ChecklistItemUpdate)
+	}
+	p.Checklist[id] = nil
+}
+
+// checklistItem returns the pending update for id, so several changes to the
+// same item end up in a single entry.
+func (p *PatchTaskDetailsParams) checklistItem(id string) *ChecklistItemUpdate {
+	if p.Checklist == nil {
+		p.Checklist = make(map[string]*ChecklistItemUpdate)
+	}
+
+	item := p.Checklist[id]
+	if item == nil {
+		item = &ChecklistItemUpdate{OdataType: checklistItemType}
+		p.Checklist[id] = item
+	}
+
+	return item
+}
+
+// AddReference adds a reference to rawURL, or updates it if the task
+// already references it. refType is one of the plannerExternalReference
+// types, e.g. "Word", "Excel" or "Other".
+func (p *PatchTaskDetailsParams) AddReference(rawURL, alias, refType string) {
+	ref := p.reference(rawURL)
+	ref.Alias = alias
+	ref.Type = refType
+}
+
+// ReorderReference moves a reference to the position given by
+// previewPriority, which is an order hint.
+func (p *PatchTaskDetailsParams) ReorderReference(rawURL, previewPriority string) {
+	p.reference(rawURL).PreviewPriority = previewPriority
+}
+
+func (p *PatchTaskDetailsParams) RemoveReference(rawURL string) {
+	if p.References == nil {
+		p.References = make(map[string]*ExternalReferenceUpdate)
+	}
+	p.References[EncodeReferenceKey(rawURL)] = nil
+}
+
+func (p *PatchTaskDetailsParams) reference(rawURL string) *ExternalReferenceUpdate {
+	if p.References == nil {
+		p.References = make(map[string]*ExternalReferenceUpdate)
+	}
+
+	key := EncodeReferenceKey(rawURL)
+	ref := p.References[key]
+	if ref == nil {
+		ref = &ExternalReferenceUpdate{OdataType: externalReferenceType}
+		p.References[key] = ref
+	}
+
+	return ref
+}
+
+// referenceKeyEncoder escapes the characters Graph doesn't allow in
+// reference keys. "%" goes first, so the escapes themselves aren't escaped.
+var (
+	referenceKeyEncoder = strings.NewReplacer("%", "%25", "@", "%40", ".", "%2E", ":", "%3A", "#", "%23")
+	referenceKeyDecoder = strings.NewReplacer("%40", "@", "%2E", ".", "%3A", ":", "%23", "#", "%25", "%")
+)
+
+// EncodeReferenceKey turns a URL into the key of TaskDetails.References.
+func EncodeReferenceKey(rawURL string) string {
+	return referenceKeyEncoder.Replace(rawURL)
+}
+
+// DecodeReferenceKey turns a key of TaskDetails.References back into a URL.
+func DecodeReferenceKey(key string) string {
+	return referenceKeyDecoder.Replace(key)
+}
+
+// newGUID returns a random (version 4) UUID, as used for checklist item IDs.
+func newGUID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -1,7 +1,7 @@
 package graph
 
 import (
-	"strings"
+	"slices"
 	"time"
 )
 
@@ -29,20 +29,28 @@ type AssignedLicense struct {
 	DisabledPlans []string `json:"disabledPlans"`
 }
 
-func userSelectParams(params []string) string {
-	var sb strings.Builder
+var defaultUserFields = []string{
+	"id",
+	"displayName",
+	"mail",
+	"userPrincipalName",
+	"accountEnabled",
+	"createdDateTime",
+	"deletedDateTime",
+	"employeeLeaveDateTime",
+}
+
+// userQuery returns a copy of q which also selects defaultUserFields.
+func userQuery(q *Query) *Query {
+	ret := q.clone()
 
-	params = append([]string{
-		"id",
-		"displayName",
-		"mail",
-		"userPrincipalName",
-		"accountEnabled",
-		"createdDateTime",
-		"deletedDateTime",
-		"employeeLeaveDateTime",
-	}, params...)
+	var selects []string
+	for _, field := range append(slices.Clone(defaultUserFields), ret.selects...) {
+		if !slices.Contains(selects, field) {
+			selects = append(selects, field)
+		}
+	}
+	ret.selects = selects
 
-	sb.WriteString(strings.Join(params, ","))
-	return sb.String()
+	return ret
 }
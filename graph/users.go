@@ -2,14 +2,19 @@ package graph
 
 import (
 	"context"
+	"iter"
 )
 
-const usersResource string = "users"
+const (
+	usersResource string = "users"
+	meResource    string = "me"
+)
 
 type UsersRequestBuilder struct {
-	c            *Client
-	path         string
-	selectParams []string
+	c        *Client
+	path     string
+	query    *Query
+	maxPages int
 }
 
 func (c *Client) Users() *UsersRequestBuilder {
@@ -19,33 +24,61 @@ func (c *Client) Users() *UsersRequestBuilder {
 	}
 }
 
+// Query sets the OData query options for the request.
+func (r *UsersRequestBuilder) Query(q *Query) *UsersRequestBuilder {
+	r.query = q
+	return r
+}
+
+// Select adds fields to $select, on top of the fields always selected for
+// a User. The query set with Query is copied first, so it isn't changed.
 func (r *UsersRequestBuilder) Select(params ...string) *UsersRequestBuilder {
-	r.selectParams = append(r.selectParams, params...)
+	r.query = r.query.clone()
+	r.query.Select(params...)
 	return r
 }
 
-type GetUsersResponse struct {
-	Count int    `json:"@odatta.count"`
-	Value []User `json:"value"`
+type GetUsersResponse = Page[User]
+
+// MaxPages limits the number of pages fetched by Get. A value <= 0 (the
+// default) fetches every page.
+func (r *UsersRequestBuilder) MaxPages(n int) *UsersRequestBuilder {
+	r.maxPages = n
+	return r
 }
 
 func (r *UsersRequestBuilder) Get(ctx context.Context) ([]User, error) {
-	var ret GetUsersResponse
+	query := userQuery(r.query)
+
+	return getAll[User](ctx, r.c, query.url(r.path), query.header(), r.maxPages)
+}
 
-	selectParams := userSelectParams(r.selectParams)
+func (r *UsersRequestBuilder) Pages() *Pager[User] {
+	query := userQuery(r.query)
 
-	if err := get(ctx, r.c, r.path+"?$select"+selectParams, &ret); err != nil {
-		return nil, err
-	}
+	return newPager[User](r.c, query.url(r.path), query.header(), r.maxPages)
+}
 
-	return ret.Value, nil
+// All iterates over every User, see Pager.All.
+func (r *UsersRequestBuilder) All(ctx context.Context) iter.Seq2[User, error] {
+	return r.Pages().All(ctx)
+}
+
+// Me returns the signed-in user. It requires a delegated token, e.g. from the
+// device code flow.
+func (c *Client) Me() *UserRequestBuilder {
+	return &UserRequestBuilder{
+		Id:   meResource,
+		c:    c,
+		path: joinPath(c.BaseURL, meResource),
+	}
 }
 
 type UserRequestBuilder struct {
-	Id           string
-	c            *Client
-	path         string
-	selectParams []string
+	Id    string
+	c     *Client
+	path  string
+	query *Query
 }
 
 func (r *UsersRequestBuilder) ById(id string) *UserRequestBuilder {
@@ -56,18 +89,26 @@ func (r *UsersRequestBuilder) ById(id string) *UserRequestBuilder {
 	}
 }
 
+// Query sets the OData query options for the request.
+func (r *UserRequestBuilder) Query(q *Query) *UserRequestBuilder {
+	r.query = q
+	return r
+}
+
+// Select adds fields to $select, on top of the fields always selected for
+// a User. The query set with Query is copied first, so it isn't changed.
 func (r *UserRequestBuilder) Select(params ...string) *UserRequestBuilder {
-	r.selectParams = append(r.selectParams, params...)
+	r.query = r.query.clone()
+	r.query.Select(params...)
 	return r
 }
 
 func (r *UserRequestBuilder) Get(ctx context.Context) (User, error) {
 	var ret User
 
-	selectParams := userSelectParams(r.selectParams)
+	query := userQuery(r.query)
 
-	// TODO: fix this
-	if err := get(ctx, r.c, r.path+"?$select="+selectParams, &ret); err != nil {
+	if err := get(ctx, r.c, query.url(r.path), query.header(), &ret); err != nil {
 		return ret, err
 	}
 
@@ -29,6 +29,11 @@ func init() {
 	bucketUpdateCmd.Flags().StringVar(&bucketId, "id", "", "ID of the Planner bucket")
 	bucketUpdateCmd.MarkFlagRequired("id")
 
+	bucketCmd.AddCommand(bucketDeleteCmd)
+	bucketDeleteCmd.Flags().StringVar(&bucketId, "id", "", "ID of the Planner bucket")
+	bucketDeleteCmd.Flags().BoolVarP(&skipConfirm, "yes", "y", false, "delete without asking for confirmation")
+	bucketDeleteCmd.MarkFlagRequired("id")
+
 	bucketCmd.AddCommand(bucketCreateCmd)
 	bucketCreateCmd.Flags().StringVar(&plannerId, "plan-id", "", "Planner plan ID for which to create new bucket")
 	bucketCreateCmd.MarkFlagRequired("plan-id")
@@ -44,7 +49,7 @@ var bucketUpdateCmd = &cobra.Command{
 		}
 
 		bucket, err := client.Planner().Buckets().ById(bucketId).Patch(cmd.Context(), graph.PatchBucketParams{
-			Name: updateBucketName,
+			Name: graph.Set(updateBucketName),
 		})
 		if err != nil {
 			return err
@@ -77,3 +82,20 @@ var bucketCreateCmd = &cobra.Command{
 		return nil
 	},
 }
+
+var bucketDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "delete a Planner bucket by ID, along with its tasks",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !confirm(cmd, fmt.Sprintf("delete Planner bucket %s and all of its tasks?", bucketId)) {
+			return nil
+		}
+
+		if err := client.Planner().Buckets().ById(bucketId).Delete(cmd.Context()); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "bucket %s deleted\n", bucketId)
+		return nil
+	},
+}
@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 
 	"github.com/alamo-ds/msgraph/graph"
@@ -28,6 +30,9 @@ func init() {
 
 	groupsGetCmd.Flags().StringVar(&groupId, "id", "", "Microsoft Group ID")
 	groupsGetCmd.Flags().BoolVar(&getThreads, "threads", false, "get threads associated with the group ID")
+	groupsGetCmd.Flags().BoolVar(&allPages, "all", false, "fetch every page of results")
+	groupsGetCmd.Flags().IntVar(&maxPages, "max-pages", 1, "maximum number of pages of results to fetch")
+	groupsGetCmd.MarkFlagsMutuallyExclusive("all", "max-pages")
 }
 
 var groupsGetCmd = &cobra.Command{
@@ -60,7 +65,7 @@ func handleGetGroupbyId(ctx context.Context, w io.Writer) error {
 }
 
 func handleGetGroups(ctx context.Context, w io.Writer) error {
-	groups, err := client.Groups().Get(ctx)
+	groups, err := client.Groups().MaxPages(pageLimit()).Get(ctx)
 	if err != nil {
 		return err
 	}
@@ -70,7 +75,7 @@ func handleGetGroups(ctx context.Context, w io.Writer) error {
 }
 
 func handleGetGroupThreads(ctx context.Context, w io.Writer) error {
-	threads, err := client.Groups().ById(groupId).Threads().Get(ctx)
+	threads, err := client.Groups().ById(groupId).Threads().MaxPages(pageLimit()).Get(ctx)
 	if err != nil {
 		return err
 	}
@@ -78,3 +83,224 @@ func handleGetGroupThreads(ctx context.Context, w io.Writer) error {
 	jsonPrint(w, threads)
 	return nil
 }
+
+var (
+	memberOwners     bool
+	memberTransitive bool
+	memberUsers      []string
+)
+
+func init() {
+	groupsCmd.AddCommand(groupsMembersCmd)
+	groupsMembersCmd.PersistentFlags().StringVar(&groupId, "id", "", "Microsoft Group ID")
+	groupsMembersCmd.PersistentFlags().BoolVar(&memberOwners, "owners", false, "manage the owners of the group instead of its members")
+	groupsMembersCmd.MarkPersistentFlagRequired("id")
+
+	groupsMembersCmd.AddCommand(groupsMembersListCmd, groupsMembersAddCmd, groupsMembersRemoveCmd)
+	groupsMembersListCmd.Flags().BoolVar(&memberTransitive, "transitive", false, "include the members of nested groups")
+	groupsMembersListCmd.MarkFlagsMutuallyExclusive("owners", "transitive")
+
+	for _, cmd := range []*cobra.Command{groupsMembersAddCmd, groupsMembersRemoveCmd} {
+		cmd.Flags().StringArrayVar(&memberUsers, "user", nil, "user principal name (e.g. alice@contoso.com) or object ID; can be repeated")
+		cmd.MarkFlagRequired("user")
+	}
+}
+
+var groupsMembersCmd = &cobra.Command{
+	Use:   "members",
+	Short: "manage the members or owners of a group",
+}
+
+func groupMembers() *graph.MembersRequestBuilder {
+	if memberOwners {
+		return client.Groups().ById(groupId).Owners()
+	}
+
+	return client.Groups().ById(groupId).Members()
+}
+
+var groupsMembersListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list the members or owners of a group",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var (
+			members []graph.DirectoryObject
+			err     error
+		)
+
+		if memberTransitive {
+			members, err = client.Groups().ById(groupId).TransitiveMembers().Get(cmd.Context())
+		} else {
+			members, err = groupMembers().Get(cmd.Context())
+		}
+		if err != nil {
+			return err
+		}
+
+		jsonPrint(cmd.OutOrStdout(), members)
+		return nil
+	},
+}
+
+var groupsMembersAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "add users to the members or owners of a group",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ids, err := resolveUserIDs(cmd.Context(), memberUsers)
+		if err != nil {
+			return err
+		}
+
+		for i, id := range ids {
+			if err := groupMembers().Add(cmd.Context(), id); err != nil {
+				return fmt.Errorf("couldn't add %s: %w", memberUsers[i], err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s added to group %s\n", memberUsers[i], groupId)
+		}
+
+		return nil
+	},
+}
+
+var groupsMembersRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "remove users from the members or owners of a group",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ids, err := resolveUserIDs(cmd.Context(), memberUsers)
+		if err != nil {
+			return err
+		}
+
+		for i, id := range ids {
+			if err := groupMembers().Remove(cmd.Context(), id); err != nil {
+				return fmt.Errorf("couldn't remove %s: %w", memberUsers[i], err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s removed from group %s\n", memberUsers[i], groupId)
+		}
+
+		return nil
+	},
+}
+
+var (
+	groupName        string
+	groupNickname    string
+	groupDescription string
+	groupVisibility  string
+	groupSecurity    bool
+	groupOwners      []string
+	groupMemberUsers []string
+)
+
+func init() {
+	groupsCmd.AddCommand(groupsCreateCmd, groupsUpdateCmd, groupsDeleteCmd, groupsRestoreCmd)
+
+	groupsCreateCmd.Flags().StringVar(&groupName, "name", "", "display name of the group")
+	groupsCreateCmd.Flags().StringVar(&groupNickname, "mail-nickname", "", "mail alias of the group")
+	groupsCreateCmd.Flags().StringVar(&groupDescription, "description", "", "description of the group")
+	groupsCreateCmd.Flags().StringVar(&groupVisibility, "visibility", "Private", "visibility of a Microsoft 365 group: Private or Public")
+	groupsCreateCmd.Flags().BoolVar(&groupSecurity, "security", false, "create a security group instead of a Microsoft 365 group")
+	groupsCreateCmd.Flags().StringArrayVar(&groupOwners, "owner", nil, "user principal name or ID of an owner; can be repeated")
+	groupsCreateCmd.Flags().StringArrayVar(&groupMemberUsers, "member", nil, "user principal name or ID of a member; can be repeated")
+	groupsCreateCmd.MarkFlagRequired("name")
+	groupsCreateCmd.MarkFlagRequired("mail-nickname")
+
+	groupsUpdateCmd.Flags().StringVar(&groupId, "id", "", "Microsoft Group ID")
+	groupsUpdateCmd.Flags().StringVar(&groupName, "name", "", "new display name of the group")
+	groupsUpdateCmd.Flags().StringVar(&groupDescription, "description", "", "new description of the group")
+	groupsUpdateCmd.Flags().StringVar(&groupVisibility, "visibility", "", "new visibility of the group: Private or Public")
+	groupsUpdateCmd.MarkFlagRequired("id")
+
+	groupsDeleteCmd.Flags().StringVar(&groupId, "id", "", "Microsoft Group ID")
+	groupsDeleteCmd.Flags().BoolVarP(&skipConfirm, "yes", "y", false, "delete without asking for confirmation")
+	groupsDeleteCmd.MarkFlagRequired("id")
+
+	groupsRestoreCmd.Flags().StringVar(&groupId, "id", "", "ID of the deleted group")
+	groupsRestoreCmd.MarkFlagRequired("id")
+}
+
+var groupsCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "create a Microsoft 365 or security group",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		params := graph.NewUnifiedGroup(groupName, groupNickname, groupVisibility)
+		if groupSecurity {
+			params = graph.NewSecurityGroup(groupName, groupNickname)
+		}
+		params.Description = groupDescription
+
+		var err error
+		if params.Owners, err = resolveUserIDs(cmd.Context(), groupOwners); err != nil {
+			return err
+		}
+		if params.Members, err = resolveUserIDs(cmd.Context(), groupMemberUsers); err != nil {
+			return err
+		}
+
+		group, err := client.Groups().Post(cmd.Context(), params)
+		if err != nil {
+			return err
+		}
+
+		jsonPrint(cmd.OutOrStdout(), group)
+		return nil
+	},
+}
+
+var groupsUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "update a group by ID",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var params graph.PatchGroupParams
+		if cmd.Flags().Changed("name") {
+			params.DisplayName = graph.Set(groupName)
+		}
+		if cmd.Flags().Changed("description") {
+			params.Description = graph.Set(groupDescription)
+		}
+		if cmd.Flags().Changed("visibility") {
+			params.Visibility = graph.Set(groupVisibility)
+		}
+		if params == (graph.PatchGroupParams{}) {
+			return errors.New("nothing to update")
+		}
+
+		if err := client.Groups().ById(groupId).Patch(cmd.Context(), params); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "group %s updated\n", groupId)
+		return nil
+	},
+}
+
+var groupsDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "delete a group by ID; it can be restored for 30 days",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !confirm(cmd, fmt.Sprintf("delete group %s?", groupId)) {
+			return nil
+		}
+
+		if err := client.Groups().ById(groupId).Delete(cmd.Context()); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "group %s deleted\n", groupId)
+		return nil
+	},
+}
+
+var groupsRestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "restore a deleted group by ID",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		group, err := client.Groups().Restore(cmd.Context(), groupId)
+		if err != nil {
+			return err
+		}
+
+		jsonPrint(cmd.OutOrStdout(), group)
+		return nil
+	},
+}
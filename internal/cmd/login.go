@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/alamo-ds/msgraph/graph"
+	"github.com/spf13/cobra"
+)
+
+var loginScopes []string
+
+var loginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "sign in as a user through the device code flow",
+	Long: `Sign in as a user through the device code flow, so commands act on behalf
+of that user (e.g. /me endpoints) rather than the app. The session is kept
+in token.json and refreshed as needed until logout.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := graph.LoadAzureADConfig()
+		if err != nil {
+			return err
+		}
+		if cfg.TenantID == "" || cfg.ClientID == "" {
+			return fmt.Errorf("tenant ID and client ID not set; run msgraph set first")
+		}
+		if len(loginScopes) != 0 {
+			cfg.Scopes = loginScopes
+		}
+
+		if err := graph.Login(cmd.Context(), cmd.ErrOrStderr(), cfg); err != nil {
+			return err
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), "logged in successfully")
+		return nil
+	},
+}
+
+var logoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "sign out the user signed in with login",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := graph.Logout(); err != nil {
+			return err
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), "logged out successfully")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(loginCmd)
+	rootCmd.AddCommand(logoutCmd)
+
+	loginCmd.Flags().StringSliceVar(&loginScopes, "scope", nil, "delegated permissions to request, e.g. Tasks.ReadWrite (default: the app's configured permissions)")
+}
@@ -3,7 +3,10 @@ package cmd
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
+	"maps"
+	"slices"
 
 	"github.com/alamo-ds/msgraph/graph"
 	"github.com/spf13/cobra"
@@ -20,6 +23,9 @@ var (
 	updatePlannerTitle string
 	getTasks           bool
 	getBuckets         bool
+	getPlanDetails     bool
+	createPlanTitle    string
+	boardView          string
 )
 
 func init() {
@@ -28,16 +34,24 @@ func init() {
 	plannerCmd.PersistentFlags().StringVar(&plannerId, "id", "", "ID of the Planner plan")
 	plannerCmd.PersistentFlags().StringVar(&groupId, "group-id", "", "Microsoft Group ID for which to fetch Planner plans")
 
-	plannerCmd.AddCommand(plannerGetCmd, plannerUpdateCmd)
+	plannerCmd.AddCommand(plannerGetCmd, plannerCreateCmd, plannerUpdateCmd, plannerDeleteCmd, plannerBoardCmd)
 
 	plannerGetCmd.Flags().BoolVar(&getTasks, "tasks", false, "Return tasks item for plan ID")
 	plannerGetCmd.Flags().BoolVar(&getBuckets, "buckets", false, "Return all buckets for plan ID")
 	plannerGetCmd.MarkFlagsOneRequired("id", "group-id")
 	plannerGetCmd.MarkFlagsMutuallyExclusive("id", "group-id")
-	plannerGetCmd.MarkFlagsMutuallyExclusive("tasks", "buckets")
+	plannerGetCmd.Flags().BoolVar(&getPlanDetails, "details", false, "Return details (category labels, sharing) for plan ID")
+	plannerGetCmd.MarkFlagsMutuallyExclusive("tasks", "buckets", "details")
+
+	plannerCreateCmd.Flags().StringVar(&createPlanTitle, "title", "", "title of the new Planner plan")
+	plannerCreateCmd.MarkFlagRequired("title")
 
 	plannerUpdateCmd.MarkPersistentFlagRequired("id")
 	plannerUpdateCmd.Flags().StringVar(&updatePlannerTitle, "title", "", "new title to assign to Planner plan")
+
+	plannerDeleteCmd.Flags().BoolVarP(&skipConfirm, "yes", "y", false, "delete without asking for confirmation")
+
+	plannerBoardCmd.Flags().StringVar(&boardView, "view", "bucket", "board view to print: bucket, progress or assigned")
 }
 
 var plannerGetCmd = &cobra.Command{
@@ -57,6 +71,9 @@ var plannerGetCmd = &cobra.Command{
 			if getBuckets {
 				return handleGetBucketsForPlan(ctx, out)
 			}
+			if getPlanDetails {
+				return handleGetPlanDetails(ctx, out)
+			}
 
 			return handleGetPlan(ctx, out)
 		case groupId != "":
@@ -105,6 +122,36 @@ func handleGetBucketsForPlan(ctx context.Context, w io.Writer) error {
 	return nil
 }
 
+func handleGetPlanDetails(ctx context.Context, w io.Writer) error {
+	details, err := client.Planner().ById(plannerId).Details().Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	jsonPrint(w, details)
+	return nil
+}
+
+var plannerCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "create a Planner plan in a Microsoft Group",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if groupId == "" {
+			return errors.New("value for group id not set")
+		}
+
+		plan, err := client.Groups().ById(groupId).Plans().Post(cmd.Context(), graph.PostPlanParams{
+			Title: createPlanTitle,
+		})
+		if err != nil {
+			return err
+		}
+
+		jsonPrint(cmd.OutOrStdout(), plan)
+		return nil
+	},
+}
+
 var plannerUpdateCmd = &cobra.Command{
 	Use:   "update",
 	Short: "update a Planner plan by id",
@@ -114,7 +161,7 @@ var plannerUpdateCmd = &cobra.Command{
 		}
 
 		plan, err := client.Planner().ById(plannerId).Patch(cmd.Context(), graph.PatchPlanParams{
-			Title: updatePlannerTitle,
+			Title: graph.Set(updatePlannerTitle),
 		})
 		if err != nil {
 			return err
@@ -124,3 +171,207 @@ var plannerUpdateCmd = &cobra.Command{
 		return nil
 	},
 }
+
+var plannerDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "delete a Planner plan by id, along with its buckets and tasks",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if plannerId == "" {
+			return errors.New("value for plan id not set")
+		}
+
+		if !confirm(cmd, fmt.Sprintf("delete Planner plan %s and all of its buckets and tasks?", plannerId)) {
+			return nil
+		}
+
+		if err := client.Planner().ById(plannerId).Delete(cmd.Context()); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "plan %s deleted\n", plannerId)
+		return nil
+	},
+}
+
+type boardColumn struct {
+	Name  string      `json:"name"`
+	Tasks []boardTask `json:"tasks"`
+}
+
+type boardTask struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+var plannerBoardCmd = &cobra.Command{
+	Use:   "board",
+	Short: "print a Planner plan as board columns, in board order",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if plannerId == "" {
+			return errors.New("value for plan id not set")
+		}
+
+		ctx := cmd.Context()
+		tasks, err := client.Planner().ById(plannerId).Tasks().Get(ctx)
+		if err != nil {
+			return err
+		}
+
+		var columns []boardColumn
+		switch boardView {
+		default:
+			return fmt.Errorf("unknown board view %q", boardView)
+		case "bucket":
+			columns, err = bucketBoard(ctx, tasks)
+		case "progress":
+			columns, err = progressBoard(ctx, tasks)
+		case "assigned":
+			columns, err = assignedBoard(ctx, tasks)
+		}
+		if err != nil {
+			return err
+		}
+
+		jsonPrint(cmd.OutOrStdout(), columns)
+		return nil
+	},
+}
+
+// boardFormats fetches a board format of every task through batching, keyed
+// by task ID.
+func boardFormats[T any](ctx context.Context, tasks []graph.Task, req func(*graph.TaskRequestBuilder) *graph.BatchRequest) (map[string]T, error) {
+	batch := client.Batch()
+	for _, task := range tasks {
+		batch.Add(req(client.Planner().Tasks().ById(task.ID)))
+	}
+
+	resps, err := batch.Send(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	formats := make(map[string]T, len(tasks))
+	for i, resp := range resps {
+		format, err := graph.BatchResult[T](resp)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't get board format of task %s: %w", tasks[i].ID, err)
+		}
+		formats[tasks[i].ID] = format
+	}
+
+	return formats, nil
+}
+
+// sortedColumn returns the tasks of a column sorted by their order hints.
+func sortedColumn(name string, tasks []graph.Task, hint func(graph.Task) string) boardColumn {
+	graph.SortByOrderHint(tasks, hint)
+
+	column := boardColumn{Name: name, Tasks: make([]boardTask, len(tasks))}
+	for i, task := range tasks {
+		column.Tasks[i] = boardTask{ID: task.ID, Title: task.Title}
+	}
+
+	return column
+}
+
+func bucketBoard(ctx context.Context, tasks []graph.Task) ([]boardColumn, error) {
+	buckets, err := client.Planner().ById(plannerId).Buckets().Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	graph.SortByOrderHint(buckets, func(b graph.Bucket) string { return b.OrderHint })
+
+	formats, err := boardFormats[graph.BucketTaskBoardFormat](ctx, tasks, func(r *graph.TaskRequestBuilder) *graph.BatchRequest {
+		return r.BucketTaskBoardFormat().GetRequest()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byBucket := make(map[string][]graph.Task)
+	for _, task := range tasks {
+		byBucket[task.BucketID] = append(byBucket[task.BucketID], task)
+	}
+
+	hint := func(t graph.Task) string { return formats[t.ID].OrderHint }
+	columns := make([]boardColumn, 0, len(buckets)+1)
+	for _, bucket := range buckets {
+		columns = append(columns, sortedColumn(bucket.Name, byBucket[bucket.ID], hint))
+		delete(byBucket, bucket.ID)
+	}
+
+	// tasks whose bucket wasn't fetched, e.g. because it was just deleted
+	var other []graph.Task
+	for _, task := range tasks {
+		if _, ok := byBucket[task.BucketID]; ok {
+			other = append(other, task)
+		}
+	}
+	if len(other) > 0 {
+		columns = append(columns, sortedColumn("other", other, hint))
+	}
+
+	return columns, nil
+}
+
+func progressBoard(ctx context.Context, tasks []graph.Task) ([]boardColumn, error) {
+	formats, err := boardFormats[graph.ProgressTaskBoardFormat](ctx, tasks, func(r *graph.TaskRequestBuilder) *graph.BatchRequest {
+		return r.ProgressTaskBoardFormat().GetRequest()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var notStarted, inProgress, completed []graph.Task
+	for _, task := range tasks {
+		switch task.PercentComplete {
+		case 0:
+			notStarted = append(notStarted, task)
+		case 100:
+			completed = append(completed, task)
+		default:
+			inProgress = append(inProgress, task)
+		}
+	}
+
+	hint := func(t graph.Task) string { return formats[t.ID].OrderHint }
+	return []boardColumn{
+		sortedColumn("Not started", notStarted, hint),
+		sortedColumn("In progress", inProgress, hint),
+		sortedColumn("Completed", completed, hint),
+	}, nil
+}
+
+func assignedBoard(ctx context.Context, tasks []graph.Task) ([]boardColumn, error) {
+	formats, err := boardFormats[graph.AssignedToTaskBoardFormat](ctx, tasks, func(r *graph.TaskRequestBuilder) *graph.BatchRequest {
+		return r.AssignedToTaskBoardFormat().GetRequest()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the "Unassigned" column comes first, keyed by an empty user ID
+	byAssignee := map[string][]graph.Task{"": nil}
+	for _, task := range tasks {
+		if len(task.Assignments) == 0 {
+			byAssignee[""] = append(byAssignee[""], task)
+		}
+		for userID := range task.Assignments {
+			byAssignee[userID] = append(byAssignee[userID], task)
+		}
+	}
+
+	columns := make([]boardColumn, 0, len(byAssignee))
+	for _, userID := range slices.Sorted(maps.Keys(byAssignee)) {
+		name := userID
+		if name == "" {
+			name = "Unassigned"
+		}
+
+		columns = append(columns, sortedColumn(name, byAssignee[userID], func(t graph.Task) string {
+			return formats[t.ID].OrderHint(userID)
+		}))
+	}
+
+	return columns, nil
+}
@@ -1,6 +1,18 @@
 package cmd
 
-import "github.com/spf13/cobra"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alamo-ds/msgraph/env"
+	"github.com/alamo-ds/msgraph/graph"
+	"github.com/spf13/cobra"
+)
 
 var postsCmd = &cobra.Command{
 	Use:     "posts",
@@ -18,7 +30,12 @@ var postsCmd = &cobra.Command{
 }
 
 var (
-	threadId string
+	threadId  string
+	postId    string
+	replyFile string
+	replyHTML bool
+	attach    []string
+	outDir    string
 )
 
 func init() {
@@ -28,4 +45,181 @@ func init() {
 	postsCmd.MarkFlagRequired("group-id")
 	postsCmd.Flags().StringVar(&threadId, "thread-id", "", "thread whose posts to get")
 	postsCmd.MarkFlagRequired("thread-id")
+
+	postsCmd.AddCommand(postsReplyCmd)
+	postsReplyCmd.Flags().StringVar(&groupId, "group-id", "", "ID of the group")
+	postsReplyCmd.Flags().StringVar(&threadId, "thread-id", "", "thread to reply to")
+	postsReplyCmd.Flags().StringVar(&postId, "post-id", "", "post to reply to; the thread is replied to if not set")
+	postsReplyCmd.Flags().StringVarP(&replyFile, "file", "f", "", "file from which to read the reply; read from stdin if not set")
+	postsReplyCmd.Flags().BoolVar(&replyHTML, "html", false, "send the reply as HTML instead of plain text")
+	postsReplyCmd.Flags().StringArrayVar(&attach, "attach", nil, "file to attach to the reply; can be repeated")
+	postsReplyCmd.MarkFlagRequired("group-id")
+	postsReplyCmd.MarkFlagRequired("thread-id")
+
+	postsCmd.AddCommand(postsAttachmentsCmd)
+	postsAttachmentsCmd.Flags().StringVar(&groupId, "group-id", "", "ID of the group")
+	postsAttachmentsCmd.Flags().StringVar(&threadId, "thread-id", "", "ID of the thread")
+	postsAttachmentsCmd.Flags().StringVar(&postId, "post-id", "", "post whose attachments to get")
+	postsAttachmentsCmd.Flags().StringVar(&outDir, "out-dir", "", "directory to which to save file attachments; they are only listed if not set")
+	postsAttachmentsCmd.MarkFlagRequired("group-id")
+	postsAttachmentsCmd.MarkFlagRequired("thread-id")
+	postsAttachmentsCmd.MarkFlagRequired("post-id")
+}
+
+var postsReplyCmd = &cobra.Command{
+	Use:   "reply",
+	Short: "reply to a thread or post, reading the body from stdin or a file",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		content, err := readReply(cmd)
+		if err != nil {
+			return err
+		}
+
+		body := graph.TextBody(content)
+		if replyHTML {
+			body = graph.HTMLBody(content)
+		}
+
+		attachments, err := readAttachments(attach)
+		if err != nil {
+			return err
+		}
+
+		thread := client.Groups().ById(groupId).Threads().ById(threadId)
+		if postId != "" {
+			err = thread.Posts().ById(postId).Reply(cmd.Context(), body, attachments...)
+		} else {
+			err = thread.Reply(cmd.Context(), body, attachments...)
+		}
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "reply sent to thread %s\n", threadId)
+		return nil
+	},
+}
+
+func readReply(cmd *cobra.Command) (string, error) {
+	var in = cmd.InOrStdin()
+
+	if replyFile != "" {
+		f, err := env.SafeOpen(replyFile)
+		if err != nil {
+			return "", fmt.Errorf("os.Open: %v", err)
+		}
+		defer f.Close()
+
+		in = f
+	} else {
+		stat, _ := os.Stdin.Stat()
+		if (stat.Mode() & os.ModeCharDevice) != 0 {
+			return "", errors.New("no reply provided. Either pipe it into program or specify file flag")
+		}
+	}
+
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return "", fmt.Errorf("couldn't read reply: %v", err)
+	}
+	if len(data) == 0 {
+		return "", errors.New("reply is empty")
+	}
+
+	return string(data), nil
+}
+
+func readAttachments(paths []string) ([]graph.FileAttachment, error) {
+	attachments := make([]graph.FileAttachment, 0, len(paths))
+	for _, path := range paths {
+		f, err := env.SafeOpen(path)
+		if err != nil {
+			return nil, fmt.Errorf("os.Open: %v", err)
+		}
+
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("couldn't read %s: %v", path, err)
+		}
+
+		attachments = append(attachments, graph.NewFileAttachment(filepath.Base(path), data))
+	}
+
+	return attachments, nil
+}
+
+var postsAttachmentsCmd = &cobra.Command{
+	Use:   "attachments",
+	Short: "list the attachments of a post, or save them to a directory",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		builder := client.Groups().ById(groupId).Threads().ById(threadId).Posts().ById(postId).Attachments()
+
+		// contentBytes is left out, files are downloaded one at a time below
+		q := graph.NewQuery().Select("id", "name", "contentType", "size", "isInline", "lastModifiedDateTime")
+		attachments, err := builder.Query(q).Get(ctx)
+		if err != nil {
+			return err
+		}
+
+		if outDir == "" {
+			jsonPrint(cmd.OutOrStdout(), attachments)
+			return nil
+		}
+
+		if err := os.MkdirAll(outDir, 0o755); err != nil {
+			return fmt.Errorf("couldn't create %s: %v", outDir, err)
+		}
+
+		saved := make(map[string]bool)
+		for _, attachment := range attachments {
+			if _, ok := attachment.File(); !ok {
+				fmt.Fprintf(cmd.ErrOrStderr(), "skipping %s: not a file\n", attachment.Name)
+				continue
+			}
+
+			path := attachmentPath(attachment, saved)
+			if err := saveAttachment(ctx, builder.ById(attachment.ID), path); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "saved %s\n", path)
+		}
+
+		return nil
+	},
+}
+
+// attachmentPath returns where to save the attachment in outDir. Posts can
+// have several attachments with the same name, so all but the first get the
+// attachment ID appended rather than overwriting each other.
+func attachmentPath(a graph.Attachment, saved map[string]bool) string {
+	name := a.Name
+	if name == "" {
+		name = a.ID
+	}
+	// the name comes from the post's author, so keep it inside outDir
+	name = filepath.Base(filepath.Clean("/" + name))
+
+	if saved[name] {
+		ext := filepath.Ext(name)
+		name = strings.TrimSuffix(name, ext) + "-" + strings.ReplaceAll(a.ID, "/", "_") + ext
+	}
+	saved[name] = true
+
+	return filepath.Join(outDir, name)
+}
+
+func saveAttachment(ctx context.Context, r *graph.AttachmentRequestBuilder, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("os.Create: %v", err)
+	}
+	defer f.Close()
+
+	if _, err := r.Download(ctx, f); err != nil {
+		return err
+	}
+
+	return f.Close()
 }
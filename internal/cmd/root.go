@@ -2,11 +2,14 @@ package cmd
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"os"
 	"os/exec"
 
 	"github.com/alamo-ds/msgraph/graph"
+	"github.com/s-hammon/p"
 	"github.com/spf13/cobra"
 )
 
@@ -15,17 +18,21 @@ var rootCmd = &cobra.Command{
 	Short:              "interact with your Microsoft Graph resources",
 	PersistentPreRunE:  clientPreRun,
 	PersistentPostRunE: clientPostRun,
+	SilenceErrors:      true,
 }
 
 var (
-	tenantId     string
-	clientId     string
-	clientSecret string
+	tenantId  string
+	clientId  string
+	certPath  string
+	cloudName string
 )
 
-func Execute(args []string, in io.Reader, out, err io.Writer) int {
+func Execute(args []string, in io.Reader, out, stderr io.Writer) int {
 	ctx := context.Background()
 	if err := rootCmd.ExecuteContext(ctx); err != nil {
+		printErr(stderr, err)
+
 		if exitErr, ok := err.(*exec.ExitError); ok {
 			return exitErr.ExitCode()
 		} else {
@@ -36,17 +43,82 @@ func Execute(args []string, in io.Reader, out, err io.Writer) int {
 	return 0
 }
 
+// printErr writes err to w. Graph errors also get their error code and
+// request IDs on separate lines, since these are what Microsoft support asks
+// for.
+func printErr(w io.Writer, err error) {
+	fmt.Fprintln(w, "Error:", err)
+
+	var graphErr *graph.Error
+	if !errors.As(err, &graphErr) {
+		return
+	}
+
+	if graphErr.Code != "" {
+		fmt.Fprintln(w, "  code:             ", graphErr.Code)
+	}
+	if graphErr.RequestID != "" {
+		fmt.Fprintln(w, "  request-id:       ", graphErr.RequestID)
+	}
+	if graphErr.ClientRequestID != "" {
+		fmt.Fprintln(w, "  client-request-id:", graphErr.ClientRequestID)
+	}
+	if graphErr.Date != "" {
+		fmt.Fprintln(w, "  date:             ", graphErr.Date)
+	}
+}
+
 // TODO: do real pre-run checks
 func clientPreRun(cmd *cobra.Command, args []string) error {
-	tenantId = os.Getenv("TENANT_ID")
-	clientId = os.Getenv("CLIENT_ID")
-	clientSecret = os.Getenv("CLIENT_SECRET")
+	tenantId = p.Coalesce(tenantId, os.Getenv("TENANT_ID"))
+	clientId = p.Coalesce(clientId, os.Getenv("CLIENT_ID"))
 
-	client = graph.NewClient(cmd.Context(), clientSecret)
-	return nil
+	if !needsClient(cmd) {
+		return nil
+	}
+
+	cfg, err := graph.LoadAzureADConfig()
+	if err != nil {
+		return err
+	}
+
+	// a signed-in user takes precedence over app credentials until logout
+	creds := []graph.Credential{graph.UserCredential(), graph.EnvCredential()}
+	if cfg.CertPath != "" {
+		creds = append(creds, graph.CertificateFileCredential(cfg.CertPath, os.Getenv("CLIENT_CERT_PASSWORD")))
+	}
+
+	client, err = graph.New(cmd.Context(), graph.WithConfig(cfg), graph.WithCredential(graph.ChainCredential(creds...)))
+	switch {
+	case errors.Is(err, graph.ErrMissingTenantID), errors.Is(err, graph.ErrMissingClientID):
+		return fmt.Errorf("invalid config, run msgraph set: %w", err)
+	case errors.Is(err, graph.ErrNoCredential):
+		return fmt.Errorf("%w: run msgraph login, set CLIENT_SECRET or store a certificate with msgraph set --cert-path", err)
+	}
+
+	return err
+}
+
+// needsClient reports whether cmd talks to Graph. The others shouldn't fail
+// on the config or session they're about to replace.
+func needsClient(cmd *cobra.Command) bool {
+	switch cmd {
+	case setCmd, loginCmd, logoutCmd:
+		return false
+	}
+
+	for ; cmd != nil; cmd = cmd.Parent() {
+		if name := cmd.Name(); name == "help" || name == "completion" {
+			return false
+		}
+	}
+
+	return true
 }
 
 func clientPostRun(cmd *cobra.Command, args []string) error {
-	client.Close()
+	if client != nil {
+		client.Close()
+	}
 	return nil
 }
@@ -3,6 +3,9 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/alamo-ds/msgraph/env"
 	"github.com/alamo-ds/msgraph/graph"
@@ -20,17 +23,30 @@ var setCmd = &cobra.Command{
 			err = flagErr("tenant-id")
 		} else if clientId == "" {
 			err = flagErr("client-id")
-		} else if clientSecret == "" {
-			err = flagErr("client-secret")
+		} else {
+			_, err = graph.LookupCloud(cloudName)
+		}
+		if err != nil || certPath == "" {
+			return err
 		}
 
+		// check the certificate now rather than on the next command
+		if certPath, err = filepath.Abs(certPath); err != nil {
+			return err
+		}
+		_, err = graph.LoadCertificate(certPath, os.Getenv("CLIENT_CERT_PASSWORD"))
 		return err
 	},
 	Run: func(cmd *cobra.Command, args []string) {
+		cloud, _ := graph.LookupCloud(cloudName)
 		cfg := graph.AzureADConfig{
 			TenantID: tenantId,
 			ClientID: clientId,
-			Scopes:   []string{graph.DefaultScopes},
+			Scopes:   []string{cloud.DefaultScope()},
+			CertPath: certPath,
+		}
+		if cloud != graph.CloudGlobal {
+			cfg.Cloud = cloud.Name
 		}
 
 		data, _ := json.MarshalIndent(cfg, "", "  ") //#nosec G117
@@ -44,6 +60,8 @@ func init() {
 
 	setCmd.Flags().StringVar(&tenantId, "tenant-id", "", "")
 	setCmd.Flags().StringVar(&clientId, "client-id", "", "")
+	setCmd.Flags().StringVar(&certPath, "cert-path", "", "PEM or PKCS#12 certificate to authenticate with instead of CLIENT_SECRET")
+	setCmd.Flags().StringVar(&cloudName, "cloud", "", "national cloud of the tenant: "+strings.Join(graph.CloudNames(), ", ")+" (default global)")
 }
 
 func flagErr(envVar string) error {
@@ -1,10 +1,14 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/alamo-ds/msgraph/env"
 	"github.com/alamo-ds/msgraph/graph"
@@ -21,7 +25,13 @@ var (
 	taskId          string
 	details         bool
 	updateTaskTitle string
+	percentComplete int
+	clearDueDate    bool
 	taskFile        string
+	taskDescription string
+	checklistTitle  string
+	checklistItem   string
+	assignUsers     []string
 )
 
 func init() {
@@ -33,12 +43,48 @@ func init() {
 	taskGetCmd.MarkPersistentFlagRequired("id")
 
 	taskCmd.AddCommand(taskUpdateCmd)
+	taskUpdateCmd.Flags().StringVar(&taskId, "id", "", "ID of the Planner task")
 	taskUpdateCmd.Flags().StringVar(&updateTaskTitle, "title", "", "new title to assign to Planner task")
+	taskUpdateCmd.Flags().IntVar(&percentComplete, "percent-complete", 0, "new progress of the Planner task, from 0 to 100")
+	taskUpdateCmd.Flags().BoolVar(&clearDueDate, "clear-due-date", false, "remove the due date of the Planner task")
+	taskUpdateCmd.MarkFlagRequired("id")
+
+	taskCmd.AddCommand(taskDeleteCmd)
+	taskDeleteCmd.Flags().StringVar(&taskId, "id", "", "ID of the Planner task")
+	taskDeleteCmd.Flags().BoolVarP(&skipConfirm, "yes", "y", false, "delete without asking for confirmation")
+	taskDeleteCmd.MarkFlagRequired("id")
 
 	taskCmd.AddCommand(taskCreateCmd)
 	taskCreateCmd.Flags().StringVar(&plannerId, "plan-id", "", "plan ID to which to add task")
 	taskCreateCmd.Flags().StringVarP(&taskFile, "file", "f", "", "file from which to read new tasks")
 	taskCreateCmd.MarkFlagRequired("plan-id")
+
+	taskCmd.AddCommand(taskDescribeCmd)
+	taskDescribeCmd.Flags().StringVar(&taskId, "id", "", "ID of the Planner task")
+	taskDescribeCmd.Flags().StringVar(&taskDescription, "description", "", "new description of the task, or empty to clear it; read from stdin if not set")
+	taskDescribeCmd.MarkFlagRequired("id")
+
+	taskCmd.AddCommand(taskChecklistCmd)
+	taskChecklistCmd.PersistentFlags().StringVar(&taskId, "id", "", "ID of the Planner task")
+	taskChecklistCmd.MarkPersistentFlagRequired("id")
+
+	taskChecklistCmd.AddCommand(taskChecklistAddCmd)
+	taskChecklistAddCmd.Flags().StringVar(&checklistTitle, "title", "", "title of the checklist item")
+	taskChecklistAddCmd.MarkFlagRequired("title")
+
+	for _, cmd := range []*cobra.Command{taskAssignCmd, taskUnassignCmd} {
+		taskCmd.AddCommand(cmd)
+		cmd.Flags().StringVar(&taskId, "id", "", "ID of the Planner task")
+		cmd.Flags().StringArrayVar(&assignUsers, "user", nil, "user principal name (e.g. alice@contoso.com) or ID of the user; can be repeated")
+		cmd.MarkFlagRequired("id")
+		cmd.MarkFlagRequired("user")
+	}
+
+	for _, cmd := range []*cobra.Command{taskChecklistCheckCmd, taskChecklistUncheckCmd, taskChecklistRemoveCmd} {
+		taskChecklistCmd.AddCommand(cmd)
+		cmd.Flags().StringVar(&checklistItem, "item", "", "ID or title of the checklist item")
+		cmd.MarkFlagRequired("item")
+	}
 }
 
 var taskGetCmd = &cobra.Command{
@@ -73,14 +119,22 @@ var taskUpdateCmd = &cobra.Command{
 	Short: "update a Planner task by ID",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// TODO: add a config flag, obviously there are more things
-		// than just the title that we can update.
-		if updateTaskTitle == "" {
-			return errors.New("value for new title not set")
+		// than these that we can update.
+		var params graph.PatchTaskParams
+		if cmd.Flags().Changed("title") {
+			if updateTaskTitle == "" {
+				return errors.New("value for new title not set")
+			}
+			params.Title = graph.Set(updateTaskTitle)
+		}
+		if cmd.Flags().Changed("percent-complete") {
+			params.PercentComplete = graph.Set(percentComplete)
+		}
+		if clearDueDate {
+			params.DueDateTime = graph.Null[time.Time]()
 		}
 
-		task, err := client.Planner().Tasks().ById(taskId).Patch(cmd.Context(), graph.PatchTaskParams{
-			Title: updateTaskTitle,
-		})
+		task, err := client.Planner().Tasks().ById(taskId).Patch(cmd.Context(), params)
 		if err != nil {
 			return err
 		}
@@ -125,3 +179,201 @@ var taskCreateCmd = &cobra.Command{
 		return nil
 	},
 }
+
+var taskDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "delete a Planner task by ID",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !confirm(cmd, fmt.Sprintf("delete Planner task %s?", taskId)) {
+			return nil
+		}
+
+		if err := client.Planner().Tasks().ById(taskId).Delete(cmd.Context()); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "task %s deleted\n", taskId)
+		return nil
+	},
+}
+
+var taskDescribeCmd = &cobra.Command{
+	Use:   "describe",
+	Short: "set the description of a Planner task",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		description := taskDescription
+		if !cmd.Flags().Changed("description") {
+			stat, _ := os.Stdin.Stat()
+			if (stat.Mode() & os.ModeCharDevice) != 0 {
+				return errors.New("no description provided. Either pipe it into program or specify description flag")
+			}
+
+			data, err := io.ReadAll(cmd.InOrStdin())
+			if err != nil {
+				return fmt.Errorf("couldn't read description: %v", err)
+			}
+			description = strings.TrimSpace(string(data))
+		}
+
+		ret, err := client.Planner().Tasks().ById(taskId).Details().Patch(cmd.Context(), graph.PatchTaskDetailsParams{
+			Description: graph.Set(description),
+		})
+		if err != nil {
+			return err
+		}
+
+		jsonPrint(cmd.OutOrStdout(), ret)
+		return nil
+	},
+}
+
+var taskChecklistCmd = &cobra.Command{
+	Use:   "checklist",
+	Short: "edit the checklist of a Planner task",
+}
+
+var taskChecklistAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "add an item to the checklist",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var params graph.PatchTaskDetailsParams
+		params.AddChecklistItem(checklistTitle)
+
+		return patchChecklist(cmd, params)
+	},
+}
+
+var taskChecklistCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "check an item of the checklist",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return editChecklistItem(cmd, (*graph.PatchTaskDetailsParams).CheckChecklistItem)
+	},
+}
+
+var taskChecklistUncheckCmd = &cobra.Command{
+	Use:   "uncheck",
+	Short: "uncheck an item of the checklist",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return editChecklistItem(cmd, (*graph.PatchTaskDetailsParams).UncheckChecklistItem)
+	},
+}
+
+var taskChecklistRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "remove an item from the checklist",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return editChecklistItem(cmd, (*graph.PatchTaskDetailsParams).RemoveChecklistItem)
+	},
+}
+
+// editChecklistItem resolves the --item flag to a checklist item ID, and
+// applies edit to it.
+func editChecklistItem(cmd *cobra.Command, edit func(*graph.PatchTaskDetailsParams, string)) error {
+	taskDetails, err := client.Planner().Tasks().ById(taskId).Details().Get(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	id, err := findChecklistItem(taskDetails, checklistItem)
+	if err != nil {
+		return err
+	}
+
+	var params graph.PatchTaskDetailsParams
+	edit(&params, id)
+
+	return patchChecklist(cmd, params)
+}
+
+// findChecklistItem matches item against the checklist item IDs first, and
+// then against their titles.
+func findChecklistItem(taskDetails graph.TaskDetails, item string) (string, error) {
+	if _, ok := taskDetails.Checklist[item]; ok {
+		return item, nil
+	}
+
+	var matches []string
+	for id, checklistItem := range taskDetails.Checklist {
+		if strings.EqualFold(checklistItem.Title, item) {
+			matches = append(matches, id)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no checklist item %q on task %s", item, taskId)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("%d checklist items titled %q, use the item ID instead", len(matches), item)
+	}
+}
+
+func patchChecklist(cmd *cobra.Command, params graph.PatchTaskDetailsParams) error {
+	ret, err := client.Planner().Tasks().ById(taskId).Details().Patch(cmd.Context(), params)
+	if err != nil {
+		return err
+	}
+
+	jsonPrint(cmd.OutOrStdout(), ret.Checklist)
+	return nil
+}
+
+var taskAssignCmd = &cobra.Command{
+	Use:   "assign",
+	Short: "assign a Planner task to users",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		userIDs, err := resolveUserIDs(cmd.Context(), assignUsers)
+		if err != nil {
+			return err
+		}
+
+		task, err := client.Planner().Tasks().ById(taskId).Assign(cmd.Context(), userIDs...)
+		if err != nil {
+			return err
+		}
+
+		jsonPrint(cmd.OutOrStdout(), task.Assignments)
+		return nil
+	},
+}
+
+var taskUnassignCmd = &cobra.Command{
+	Use:   "unassign",
+	Short: "remove users from the assignees of a Planner task",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		userIDs, err := resolveUserIDs(cmd.Context(), assignUsers)
+		if err != nil {
+			return err
+		}
+
+		task, err := client.Planner().Tasks().ById(taskId).Unassign(cmd.Context(), userIDs...)
+		if err != nil {
+			return err
+		}
+
+		jsonPrint(cmd.OutOrStdout(), task.Assignments)
+		return nil
+	},
+}
+
+// resolveUserIDs looks up the object ID of every user given by principal
+// name. Anything without an "@" is taken to be an ID already.
+func resolveUserIDs(ctx context.Context, users []string) ([]string, error) {
+	ids := make([]string, 0, len(users))
+	for _, u := range users {
+		if !strings.Contains(u, "@") {
+			ids = append(ids, u)
+			continue
+		}
+
+		user, err := client.Users().ById(u).Select("id").Get(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't find user %s: %w", u, err)
+		}
+		ids = append(ids, user.ID)
+	}
+
+	return ids, nil
+}
@@ -3,7 +3,10 @@ package cmd
 import (
 	"context"
 	"io"
+	"slices"
+	"strings"
 
+	"github.com/alamo-ds/msgraph/graph"
 	"github.com/spf13/cobra"
 )
 
@@ -27,6 +30,15 @@ func init() {
 	usersGetCmd.Flags().StringVar(&userEmail, "email", "", "Outlook address associated with the user")
 	usersGetCmd.Flags().StringArrayVar(&selectParams, "select", nil, "comma-separated values of field names to include in request")
 	usersGetCmd.MarkFlagsMutuallyExclusive("id", "email")
+	usersGetCmd.Flags().BoolVar(&allPages, "all", false, "fetch every page of results")
+	usersGetCmd.Flags().IntVar(&maxPages, "max-pages", 1, "maximum number of pages of results to fetch")
+	usersGetCmd.MarkFlagsMutuallyExclusive("all", "max-pages")
+
+	usersCmd.AddCommand(usersTasksCmd)
+	usersTasksCmd.Flags().StringVar(&userId, "id", "", "Microsoft user ID")
+	usersTasksCmd.Flags().StringVar(&userEmail, "email", "", "user principal name of the user")
+	usersTasksCmd.MarkFlagsOneRequired("id", "email")
+	usersTasksCmd.MarkFlagsMutuallyExclusive("id", "email")
 }
 
 var usersGetCmd = &cobra.Command{
@@ -46,7 +58,7 @@ var usersGetCmd = &cobra.Command{
 }
 
 func handleGetUsers(ctx context.Context, w io.Writer) error {
-	users, err := client.Users().Select(selectParams...).Get(ctx)
+	users, err := client.Users().Select(selectParams...).MaxPages(pageLimit()).Get(ctx)
 	if err != nil {
 		return err
 	}
@@ -56,7 +68,7 @@ func handleGetUsers(ctx context.Context, w io.Writer) error {
 }
 
 func handleGetUserById(ctx context.Context, w io.Writer) error {
-	user, err := client.Users().Select(selectParams...).ById(userId).Get(ctx)
+	user, err := client.Users().ById(userId).Select(selectParams...).Get(ctx)
 	if err != nil {
 		return err
 	}
@@ -64,3 +76,99 @@ func handleGetUserById(ctx context.Context, w io.Writer) error {
 	jsonPrint(w, user)
 	return nil
 }
+
+type userPlanTasks struct {
+	ID      string            `json:"id"`
+	Title   string            `json:"title"`
+	Buckets []userBucketTasks `json:"buckets"`
+}
+
+type userBucketTasks struct {
+	ID    string       `json:"id"`
+	Name  string       `json:"name"`
+	Tasks []graph.Task `json:"tasks"`
+}
+
+var usersTasksCmd = &cobra.Command{
+	Use:   "tasks",
+	Short: "get the Planner tasks assigned to a user, grouped by plan and bucket",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+
+		id := userId
+		if id == "" {
+			id = userEmail
+		}
+
+		tasks, err := client.Users().ById(id).Planner().Tasks().Get(ctx)
+		if err != nil {
+			return err
+		}
+
+		byPlan := make(map[string][]graph.Task)
+		for _, task := range tasks {
+			byPlan[task.PlanID] = append(byPlan[task.PlanID], task)
+		}
+
+		plans := make([]userPlanTasks, 0, len(byPlan))
+		for planID, planTasks := range byPlan {
+			plan, err := groupByBucket(ctx, planID, planTasks)
+			if err != nil {
+				return err
+			}
+			plans = append(plans, plan)
+		}
+		slices.SortFunc(plans, func(a, b userPlanTasks) int {
+			return strings.Compare(a.Title, b.Title)
+		})
+
+		jsonPrint(cmd.OutOrStdout(), plans)
+		return nil
+	},
+}
+
+// groupByBucket groups the tasks of a plan by bucket, both in board order.
+// Tasks whose bucket wasn't fetched, e.g. because it was just deleted, are
+// grouped last, under "unbucketed".
+func groupByBucket(ctx context.Context, planID string, tasks []graph.Task) (userPlanTasks, error) {
+	plan, err := client.Planner().ById(planID).Get(ctx)
+	if err != nil {
+		return userPlanTasks{}, err
+	}
+
+	buckets, err := client.Planner().ById(planID).Buckets().Get(ctx)
+	if err != nil {
+		return userPlanTasks{}, err
+	}
+	graph.SortByOrderHint(buckets, func(b graph.Bucket) string { return b.OrderHint })
+	graph.SortByOrderHint(tasks, func(t graph.Task) string { return t.OrderHint })
+
+	ret := userPlanTasks{ID: plan.ID, Title: plan.Title}
+	bucketIDs := make(map[string]bool, len(buckets))
+	for _, bucket := range buckets {
+		bucketIDs[bucket.ID] = true
+
+		group := userBucketTasks{ID: bucket.ID, Name: bucket.Name}
+		for _, task := range tasks {
+			if task.BucketID == bucket.ID {
+				group.Tasks = append(group.Tasks, task)
+			}
+		}
+
+		if len(group.Tasks) > 0 {
+			ret.Buckets = append(ret.Buckets, group)
+		}
+	}
+
+	unbucketed := userBucketTasks{Name: "unbucketed"}
+	for _, task := range tasks {
+		if !bucketIDs[task.BucketID] {
+			unbucketed.Tasks = append(unbucketed.Tasks, task)
+		}
+	}
+	if len(unbucketed.Tasks) > 0 {
+		ret.Buckets = append(ret.Buckets, unbucketed)
+	}
+
+	return ret, nil
+}
@@ -1,12 +1,51 @@
 package cmd
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	allPages    bool
+	maxPages    int
+	skipConfirm bool
 )
 
+// pageLimit returns the page limit requested through the --all and
+// --max-pages flags, where 0 means every page is fetched.
+func pageLimit() int {
+	if allPages {
+		return 0
+	}
+
+	return maxPages
+}
+
 func jsonPrint(w io.Writer, v any) {
 	data, _ := json.MarshalIndent(v, "", "  ")
 	fmt.Fprintln(w, string(data))
 }
+
+// confirm asks the user to confirm a destructive action on stdin, unless the
+// --yes flag was set.
+func confirm(cmd *cobra.Command, prompt string) bool {
+	if skipConfirm {
+		return true
+	}
+
+	fmt.Fprintf(cmd.ErrOrStderr(), "%s [y/N]: ", prompt)
+	answer, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	default:
+		fmt.Fprintln(cmd.ErrOrStderr(), "aborted")
+		return false
+	case "y", "yes":
+		return true
+	}
+}